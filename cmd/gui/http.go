@@ -3,17 +3,38 @@ package main
 import (
 	"html/template"
 	"net/http"
-	"strconv"
 
 	"github.com/datravis/lolachain/pkg/client"
 	"github.com/datravis/lolachain/pkg/keys"
 )
 
+// Balance is a formatted balance of one token.
+type Balance struct {
+	Symbol string
+	Amount string
+	Locked string
+}
+
+// Entry is a formatted transaction from the wallet's history. Encrypted
+// memos are shown decrypted.
+type Entry struct {
+	Height       uint64
+	Direction    string
+	Counterparty string
+	Amount       string
+	Symbol       string
+	Memo         string
+}
+
+// historyLength is how many recent transactions the wallet shows.
+const historyLength = 20
+
 // PageVariables contains variables returned to the screen.
 type PageVariables struct {
-	RKYBalance  float64
-	LOLABalance float64
-	Address     string
+	Balances []Balance
+	Items    []client.NFT
+	History  []Entry
+	Address  string
 }
 
 //RKYWallet handles GET and POST requests.
@@ -54,20 +75,57 @@ func ShowWallet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rkyBalance := 0.0
-	if _, ok := balances["RKY"]; ok {
-		rkyBalance = balances["RKY"]
+	tokens, err := client.GetTokens(validator)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	// every token held is shown, the builtin ones even when empty.
+	WalletVars := PageVariables{Address: address}
+	for _, tk := range tokens {
+		amount, ok := balances.Unlocked[tk.Symbol]
+		locked, hasLocked := balances.Locked[tk.Symbol]
+		if !ok && !hasLocked && tk.Symbol != "RKY" && tk.Symbol != "LOLA" {
+			continue
+		}
+		balance := Balance{Symbol: tk.Symbol, Amount: tk.Format(amount)}
+		if hasLocked {
+			balance.Locked = tk.Format(locked)
+		}
+		WalletVars.Balances = append(WalletVars.Balances, balance)
 	}
 
-	llaBalance := 0.0
-	if _, ok := balances["LOLA"]; ok {
-		llaBalance = balances["LOLA"]
+	WalletVars.Items, err = client.GetNFTs(validator, address)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
 	}
 
-	WalletVars := PageVariables{
-		RKYBalance:  rkyBalance,
-		LOLABalance: llaBalance,
-		Address:     address,
+	history, err := client.GetTransactions(validator, address)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	if len(history) > historyLength {
+		history = history[:historyLength]
+	}
+	for _, h := range history {
+		t := h.Transaction
+		memo, err := t.DecryptMemo(keyPair)
+		if err != nil {
+			memo = "(encrypted)"
+		}
+		entry := Entry{Height: h.Height, Direction: "from", Counterparty: t.Source, Symbol: t.Symbol, Memo: memo}
+		if t.Source == address {
+			entry.Direction, entry.Counterparty = "to", t.Destination
+		}
+		for _, tk := range tokens {
+			if tk.Symbol == t.Symbol {
+				entry.Amount = tk.Format(t.Amount)
+			}
+		}
+		WalletVars.History = append(WalletVars.History, entry)
 	}
 
 	t, err := template.ParseFiles("templates/wallet.html")
@@ -92,13 +150,17 @@ func Send(w http.ResponseWriter, r *http.Request) {
 
 	form := r.Form
 	dest := form.Get("destination")
-	amountStr := form.Get("amount")
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	symbol := form.Get("symbol")
+	tk, err := client.GetToken(validator, symbol)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	amount, err := tk.Parse(form.Get("amount"))
+	if err != nil {
+		http.Error(w, err.Error(), 400)
 		return
 	}
-	symbol := form.Get("symbol")
 	memo := form.Get("memo")
 
 	path, err := keys.GetDefaultKeyPath()
@@ -113,7 +175,11 @@ func Send(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = client.Send(validator, keyPair, dest, amount, symbol, memo)
+	if form.Get("encrypt") != "" {
+		err = client.SendEncrypted(validator, keyPair, dest, amount, symbol, memo)
+	} else {
+		err = client.Send(validator, keyPair, dest, amount, symbol, memo)
+	}
 	if err != nil {
 		http.Error(w, err.Error(), 500)
 		return
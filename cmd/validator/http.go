@@ -2,9 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/datravis/lolachain/pkg/chain"
 	"github.com/datravis/lolachain/pkg/tran"
@@ -19,10 +21,27 @@ func StartServer(bind string, c *chain.Chain) {
 	lolachain = c
 	r := mux.NewRouter()
 	r.HandleFunc("/addresses/{address}", AddressHandler)
+	r.HandleFunc("/addresses/{address}/nfts", AddressNFTsHandler)
+	r.HandleFunc("/addresses/{address}/transactions", AddressTransactionsHandler)
+	r.HandleFunc("/addresses/{owner}/allowances", AllowancesHandler)
 	r.HandleFunc("/transactions", TransactionHandler)
 	r.HandleFunc("/chain", ChainHandler)
+	r.HandleFunc("/height", HeightHandler)
 	r.HandleFunc("/pending", PendingHandler)
 	r.HandleFunc("/peers", PeersHandler)
+	r.HandleFunc("/tokens", TokensHandler)
+	r.HandleFunc("/tokens/{symbol}", TokenHandler)
+	r.HandleFunc("/tokens/{symbol}/supply", SupplyHandler)
+	r.HandleFunc("/htlcs/{id}", ContractHandler)
+	r.HandleFunc("/channels/{id}", ChannelHandler)
+	r.HandleFunc("/params", ParamsHandler)
+	r.HandleFunc("/proposals", ProposalsHandler)
+	r.HandleFunc("/proposals/{id}", ProposalHandler)
+	r.HandleFunc("/names/{name}", NameHandler)
+	r.HandleFunc("/anchors/{hash}", AnchorHandler)
+	r.HandleFunc("/nfts/{id}", NFTHandler)
+	r.HandleFunc("/markets/{base:[A-Z0-9]+}-{quote:[A-Z0-9]+}/orderbook", OrderBookHandler)
+	r.HandleFunc("/markets/{base:[A-Z0-9]+}-{quote:[A-Z0-9]+}/trades", TradesHandler)
 	http.Handle("/", r)
 
 	fmt.Printf("%s", http.ListenAndServe(bind, nil))
@@ -46,7 +65,7 @@ func PeersHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		fmt.Fprintf(w, string(peerJSON))
+		w.Write(peerJSON)
 	} else {
 		http.NotFound(w, r)
 		return
@@ -62,7 +81,19 @@ func ChainHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Fprintf(w, string(chainJSON))
+	w.Write(chainJSON)
+}
+
+// HeightHandler returns the height of the next block, so clients don't need
+// the whole chain to learn it.
+func HeightHandler(w http.ResponseWriter, r *http.Request) {
+	heightJSON, err := json.Marshal(uint64(len(lolachain.Blocks)))
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(heightJSON)
 }
 
 func PendingHandler(w http.ResponseWriter, r *http.Request) {
@@ -72,10 +103,11 @@ func PendingHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Fprintf(w, string(pendingJSON))
+	w.Write(pendingJSON)
 }
 
-// AddressHandler returns balances for the supplied address.
+// AddressHandler returns the unlocked and locked balances for the supplied
+// address.
 func AddressHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	address := vars["address"]
@@ -88,8 +120,268 @@ func AddressHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Fprintf(w, string(balancesJSON))
+	w.Write(balancesJSON)
+
+}
+
+// TokensHandler returns every token on the chain.
+func TokensHandler(w http.ResponseWriter, r *http.Request) {
+	tokensJSON, err := json.MarshalIndent(lolachain.GetTokens(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(tokensJSON)
+}
+
+// TokenHandler returns the token with the supplied symbol.
+func TokenHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	info, ok := lolachain.GetToken(vars["symbol"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	tokenJSON, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(tokenJSON)
+}
+
+// SupplyHandler returns the minted, burned and circulating supply of a token.
+func SupplyHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	info, ok := lolachain.GetToken(vars["symbol"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	supplyJSON, err := json.MarshalIndent(info.Supply, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(supplyJSON)
+}
+
+// ContractHandler returns a hash time-locked contract, including its preimage
+// once redeemed.
+func ContractHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	contract, ok := lolachain.GetContract(vars["id"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	contractJSON, err := json.MarshalIndent(contract, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(contractJSON)
+}
+
+// ChannelHandler returns a payment channel, including the update submitted
+// at close and the end of its dispute window.
+func ChannelHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
 
+	channel, ok := lolachain.GetChannel(vars["id"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	channelJSON, err := json.MarshalIndent(channel, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(channelJSON)
+}
+
+// ParamsHandler returns the consensus rules for the next block, including
+// changes approved by governance, and the approved changes still to come.
+func ParamsHandler(w http.ResponseWriter, r *http.Request) {
+	paramsJSON, err := json.MarshalIndent(lolachain.GetParams(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(paramsJSON)
+}
+
+// ProposalsHandler returns every governance proposal, the most recent first.
+func ProposalsHandler(w http.ResponseWriter, r *http.Request) {
+	proposalsJSON, err := json.MarshalIndent(lolachain.GetProposals(), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(proposalsJSON)
+}
+
+// ProposalHandler returns a governance proposal with its votes and, once
+// closed, its tally.
+func ProposalHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	proposal, ok := lolachain.GetProposal(vars["id"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	proposalJSON, err := json.MarshalIndent(proposal, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(proposalJSON)
+}
+
+// NameHandler returns the record of a registered name, including the address
+// it resolves to.
+func NameHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	record, ok := lolachain.ResolveName(vars["name"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	recordJSON, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(recordJSON)
+}
+
+// AnchorHandler returns the block and timestamp at which a hex encoded
+// SHA-256 digest was anchored.
+func AnchorHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	record, ok := lolachain.GetAnchor(strings.ToLower(vars["hash"]))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	recordJSON, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(recordJSON)
+}
+
+// NFTHandler returns a unique token along with its ownership history.
+func NFTHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	nft, history, ok := lolachain.GetNFT(vars["id"])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	nftJSON, err := json.MarshalIndent(struct {
+		chain.NFT
+		History []chain.NFTTransfer `json:"history"`
+	}{nft, history}, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(nftJSON)
+}
+
+// AddressNFTsHandler returns the unique tokens owned by the supplied address.
+func AddressNFTsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	nftsJSON, err := json.MarshalIndent(lolachain.GetNFTsForAddress(vars["address"]), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(nftsJSON)
+}
+
+// OrderBookHandler returns the open orders of a market, best price first.
+func OrderBookHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	bookJSON, err := json.MarshalIndent(lolachain.GetOrderBook(vars["base"], vars["quote"]), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(bookJSON)
+}
+
+// TradesHandler returns the trades of a market, oldest first.
+func TradesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	tradesJSON, err := json.MarshalIndent(lolachain.GetTrades(vars["base"], vars["quote"]), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(tradesJSON)
+}
+
+// AddressTransactionsHandler returns the transactions sent or received by
+// the supplied address, newest first.
+func AddressTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	historyJSON, err := json.MarshalIndent(lolachain.GetTransactionsForAddress(vars["address"]), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(historyJSON)
+}
+
+// AllowancesHandler returns every allowance granted by the supplied owner.
+func AllowancesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	allowancesJSON, err := json.MarshalIndent(lolachain.GetAllowances(vars["owner"]), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	w.Write(allowancesJSON)
 }
 
 // TransactionHandler handles posting new transactions to the blockchain.
@@ -115,6 +407,11 @@ func TransactionHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = lolachain.PostTransaction(t)
 	if err != nil {
+		var verr *tran.ValidationError
+		if errors.As(err, &verr) {
+			http.Error(w, err.Error(), 400)
+			return
+		}
 		http.Error(w, err.Error(), 500)
 		return
 	}
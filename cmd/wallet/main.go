@@ -1,12 +1,27 @@
 package main
 
 import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strconv"
+	"time"
 
+	"github.com/datravis/lolachain/pkg/channel"
 	"github.com/datravis/lolachain/pkg/client"
 	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/script"
+	"github.com/datravis/lolachain/pkg/token"
+	"github.com/datravis/lolachain/pkg/tran"
 )
 
 // TODO: This needs to be refactored. This is just a quickly thrown together
@@ -42,7 +57,11 @@ func main() {
 	switch command := args[0]; command {
 	case "balance":
 		if len(args) == 2 {
-			address = args[1]
+			address, err = client.ResolveAddress(*v, args[1])
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
 		}
 
 		balances, err := client.GetBalances(*v, address)
@@ -53,28 +72,1368 @@ func main() {
 
 		fmt.Printf("Address: %s\n", address)
 		fmt.Println("Balances:")
-		for key, val := range balances {
-			fmt.Printf("%f %s\n", val, key)
+		for key, val := range balances.Unlocked {
+			fmt.Printf("%s %s\n", formatAmount(*v, val, key), key)
 		}
-	case "send":
+		if len(balances.Locked) > 0 {
+			fmt.Println("Locked:")
+			for key, val := range balances.Locked {
+				fmt.Printf("%s %s\n", formatAmount(*v, val, key), key)
+			}
+		}
+	case "send", "send-private":
 		if len(args) != 5 {
 			fmt.Println("Requires arguments: dest amount symbol memo")
 			return
 		}
 		dest := args[1]
-		amount, err := strconv.ParseFloat(args[2], 64)
+		symbol := args[3]
+		amount, err := parseAmount(*v, args[2], symbol)
 		if err != nil {
 			fmt.Printf("Error: %s\n", err)
 			return
 		}
-		symbol := args[3]
 		memo := args[4]
-		err = client.Send(*v, keyPair, dest, amount, symbol, memo)
+		if command == "send-private" {
+			err = client.SendEncrypted(*v, keyPair, dest, amount, symbol, memo)
+		} else {
+			err = client.Send(*v, keyPair, dest, amount, symbol, memo)
+		}
 		if err != nil {
 			fmt.Printf("Error: %s\n", err)
 			return
 		}
-	default:
-		fmt.Println("Unknown command")
+	case "history":
+		history, err := client.GetTransactions(*v, address)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		for _, h := range history {
+			t := h.Transaction
+			memo, err := t.DecryptMemo(keyPair)
+			if err != nil {
+				memo = "(encrypted)"
+			}
+			direction, other := "from", t.Source
+			if t.Source == address {
+				direction, other = "to", t.Destination
+			}
+			amount := ""
+			if t.Amount > 0 {
+				amount = fmt.Sprintf("%s %s ", formatAmount(*v, t.Amount, t.Symbol), t.Symbol)
+			}
+			fmt.Printf("%d\t%s\t%s\t%s%s %s\t%s\n", h.Height, t.ID, t.TransactionType(), amount, direction, other, memo)
+		}
+	case "send-batch":
+		if len(args) != 2 && len(args) != 3 {
+			fmt.Println("Requires arguments: file [memo]")
+			return
+		}
+		outputs, err := readOutputs(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		memo := ""
+		if len(args) == 3 {
+			memo = args[2]
+		}
+
+		t, err := tran.NewBatchTransaction(address, outputs, memo, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Sent %d payments in %s\n", len(outputs), t.ID)
+	case "send-locked":
+		if len(args) != 6 {
+			fmt.Println("Requires arguments: dest amount symbol unlock-height|unlock-time memo")
+			return
+		}
+		symbol := args[3]
+		amount, err := parseAmount(*v, args[2], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		var lock tran.Lock
+		if height, err := strconv.ParseUint(args[4], 10, 64); err == nil {
+			lock.Height = height
+		} else if lock.Time, err = time.Parse(time.RFC3339, args[4]); err != nil {
+			fmt.Println("Unlock must be a block height or an RFC 3339 time")
+			return
+		}
+
+		t, err := tran.NewLockedTransferTransaction(symbol, address, args[1], amount, lock, args[5], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "stake", "unstake", "burn":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: amount symbol")
+			return
+		}
+		symbol := args[2]
+		amount, err := parseAmount(*v, args[1], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+
+		var t tran.Transaction
+		switch command {
+		case "stake":
+			t, err = tran.NewStakeTransaction(symbol, address, amount, time.Now().UTC())
+		case "unstake":
+			t, err = tran.NewUnstakeTransaction(symbol, address, amount, time.Now().UTC())
+		case "burn":
+			t, err = tran.NewBurnTransaction(symbol, address, amount, time.Now().UTC())
+		}
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "tokens":
+		tokens, err := client.GetTokens(*v)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		for _, tk := range tokens {
+			fmt.Printf("%s\t%s\t%d decimals\n", tk.Symbol, tk.Name, tk.Decimals)
+		}
+	case "supply":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: symbol")
+			return
+		}
+		tk, err := client.GetToken(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		supply, err := client.GetSupply(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Minted: %s %s\n", tk.Format(supply.Minted), tk.Symbol)
+		fmt.Printf("Burned: %s %s\n", tk.Format(supply.Burned), tk.Symbol)
+		fmt.Printf("Circulating: %s %s\n", tk.Format(supply.Circulating), tk.Symbol)
+	case "issue":
+		if len(args) != 5 && len(args) != 6 {
+			fmt.Println("Requires arguments: symbol name decimals supply [mint-authority]")
+			return
+		}
+		decimals, err := strconv.ParseUint(args[3], 10, 8)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		supply, err := token.ParseAmount(args[4], uint8(decimals))
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		p := tran.IssueToken{Symbol: args[1], Name: args[2], Decimals: uint8(decimals), Supply: supply}
+		if len(args) == 6 {
+			p.MintAuthority = args[5]
+		}
+
+		t, err := tran.NewIssueTokenTransaction(address, p, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "mint":
+		if len(args) != 4 {
+			fmt.Println("Requires arguments: dest amount symbol")
+			return
+		}
+		symbol := args[3]
+		amount, err := parseAmount(*v, args[2], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+
+		t, err := tran.NewMintTransaction(symbol, address, args[1], amount, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "swap-initiate", "swap-participate":
+		// the initiator picks the secret and locks first; the participant
+		// locks under the initiator's hash with a shorter timeout, so the
+		// initiator must redeem, revealing the secret, before either expires.
+		if command == "swap-initiate" && len(args) != 5 {
+			fmt.Println("Requires arguments: dest amount symbol timeout-blocks")
+			return
+		}
+		if command == "swap-participate" && len(args) != 6 {
+			fmt.Println("Requires arguments: dest amount symbol timeout-blocks initiator-contract")
+			return
+		}
+		symbol := args[3]
+		amount, err := parseAmount(*v, args[2], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		blocks, err := strconv.ParseUint(args[4], 10, 64)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		height, err := client.GetHeight(*v)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+
+		var secret, hash []byte
+		if command == "swap-initiate" {
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
+			hash = tran.HashSecret(secret)
+		} else {
+			// lock under the initiator's hash, and refuse a timeout that
+			// doesn't leave the initiator's contract running after ours.
+			c, err := client.GetContract(*v, args[5])
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
+			if c.State != "open" {
+				fmt.Printf("Error: contract %s is already %s\n", c.ID, c.State)
+				return
+			}
+			if height+blocks >= c.Timeout {
+				fmt.Printf("Error: timeout %d must be before the initiator's timeout %d\n", height+blocks, c.Timeout)
+				return
+			}
+			hash = c.Hash
+		}
+
+		p := tran.HTLC{Hash: hash, Timeout: height + blocks}
+		t, err := tran.NewHTLCTransaction(symbol, address, args[1], amount, p, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Contract: %s\n", t.ID)
+		fmt.Printf("Hash: %s\n", hex.EncodeToString(hash))
+		fmt.Printf("Timeout: %d\n", p.Timeout)
+		if secret != nil {
+			fmt.Printf("Secret: %s\n", hex.EncodeToString(secret))
+		}
+	case "swap-redeem":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: contract secret")
+			return
+		}
+		secret, err := hex.DecodeString(args[2])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := tran.NewHTLCRedeemTransaction(address, args[1], secret, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "swap-refund":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: contract")
+			return
+		}
+		t, err := tran.NewHTLCRefundTransaction(address, args[1], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "swap-status":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: contract")
+			return
+		}
+		c, err := client.GetContract(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Contract: %s\n", c.ID)
+		fmt.Printf("From: %s\n", c.Sender)
+		fmt.Printf("To: %s\n", c.Recipient)
+		fmt.Printf("Amount: %s %s\n", formatAmount(*v, c.Amount, c.Symbol), c.Symbol)
+		fmt.Printf("Hash: %s\n", hex.EncodeToString(c.Hash))
+		fmt.Printf("Timeout: %d\n", c.Timeout)
+		fmt.Printf("State: %s\n", c.State)
+		if c.Preimage != nil {
+			fmt.Printf("Secret: %s\n", hex.EncodeToString(c.Preimage))
+		}
+	case "channel-open":
+		if len(args) != 5 {
+			fmt.Println("Requires arguments: dest amount symbol window-blocks")
+			return
+		}
+		dest, err := client.ResolveAddress(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		symbol := args[3]
+		amount, err := parseAmount(*v, args[2], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		window, err := strconv.ParseUint(args[4], 10, 64)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := tran.NewOpenChannelTransaction(symbol, address, dest, amount, window, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		c, err := channel.FromTransaction(t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		if err := saveChannel(c); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Channel: %s\n", t.ID)
+	case "channel-pay":
+		if len(args) != 4 {
+			fmt.Println("Requires arguments: channel amount file")
+			return
+		}
+		c, err := loadChannel(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		amount, err := parseAmount(*v, args[2], c.Symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		update, err := c.Pay(keyPair, amount)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		if err := saveChannel(c); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		b, err := json.MarshalIndent(update, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		if err := ioutil.WriteFile(args[3], b, 0644); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Paid %s %s in total, %s remaining\n", formatAmount(*v, c.Paid(), c.Symbol), c.Symbol, formatAmount(*v, c.Remaining(), c.Symbol))
+	case "channel-accept":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: file")
+			return
+		}
+		var update tran.ChannelUpdate
+		b, err := ioutil.ReadFile(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		if err := json.Unmarshal(b, &update); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		c, err := loadChannel(*v, update.Channel)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		if c.Recipient != address {
+			fmt.Printf("Error: channel %s does not pay %s\n", c.ID, address)
+			return
+		}
+		received, err := c.Accept(update)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		if err := saveChannel(c); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Received %s %s, %s in total\n", formatAmount(*v, received, c.Symbol), c.Symbol, formatAmount(*v, c.Paid(), c.Symbol))
+	case "channel-close":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: channel")
+			return
+		}
+		c, err := loadChannel(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := c.CloseTransaction(address, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "channel-settle":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: channel")
+			return
+		}
+		t, err := tran.NewSettleChannelTransaction(address, args[1], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "channel":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: channel")
+			return
+		}
+		c, err := client.GetChannel(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Channel: %s\n", c.ID)
+		fmt.Printf("From: %s\n", c.Sender)
+		fmt.Printf("To: %s\n", c.Recipient)
+		fmt.Printf("Capacity: %s %s\n", formatAmount(*v, c.Capacity, c.Symbol), c.Symbol)
+		fmt.Printf("Window: %d\n", c.Window)
+		fmt.Printf("State: %s\n", c.State)
+		if c.State != "open" {
+			fmt.Printf("Paid: %s %s\n", formatAmount(*v, c.Paid, c.Symbol), c.Symbol)
+		}
+		if c.State == "closing" {
+			fmt.Printf("Closes at: %d\n", c.ClosesAt)
+		}
+		if local, err := loadChannel(*v, c.ID); err == nil && local.Paid() > 0 {
+			fmt.Printf("Latest update: %s %s\n", formatAmount(*v, local.Paid(), c.Symbol), c.Symbol)
+		}
+	case "stealth-address":
+		stealthKeys, err := loadStealthKeys()
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		stealthAddress, err := stealthKeys.Address()
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Stealth address: %s\n", stealthAddress)
+	case "send-stealth":
+		if len(args) != 5 {
+			fmt.Println("Requires arguments: stealth-address amount symbol memo")
+			return
+		}
+		symbol := args[3]
+		amount, err := parseAmount(*v, args[2], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := tran.NewStealthTransaction(symbol, address, args[1], amount, args[4], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Paid one-time address %s\n", t.Destination)
+	case "scan":
+		// finds stealth payments to this wallet and saves the key of each
+		// one-time address under the ID of the transaction that paid it.
+		if len(args) != 1 && len(args) != 2 {
+			fmt.Println("Requires arguments: [from-height]")
+			return
+		}
+		var from uint64
+		if len(args) == 2 {
+			from, err = strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
+		}
+		stealthKeys, err := loadStealthKeys()
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		blocks, err := client.GetBlocks(*v)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		for _, b := range blocks {
+			if b.Index < from {
+				continue
+			}
+			for _, t := range b.Transactions {
+				if t.Type != tran.TypeStealthTransfer {
+					continue
+				}
+				p, err := t.DecodePayload()
+				if err != nil {
+					continue
+				}
+				key, err := stealthKeys.Find(t.Destination, p.(*tran.StealthTransfer).Ephemeral)
+				if err != nil || key == nil {
+					continue
+				}
+				path, err := stealthKeyPath(t.ID)
+				if err != nil {
+					fmt.Printf("Error: %s\n", err)
+					return
+				}
+				if err := keys.WriteKeys(key, path); err != nil {
+					fmt.Printf("Error: %s\n", err)
+					return
+				}
+				balances, err := client.GetBalances(*v, t.Destination)
+				if err != nil {
+					fmt.Printf("Error: %s\n", err)
+					return
+				}
+				fmt.Printf("%d\t%s\treceived %s %s, %s unspent\n", b.Index, t.ID,
+					formatAmount(*v, t.Amount, t.Symbol), t.Symbol, formatAmount(*v, balances.Unlocked[t.Symbol], t.Symbol))
+			}
+		}
+	case "spend-stealth":
+		if len(args) != 6 {
+			fmt.Println("Requires arguments: transaction dest amount symbol memo")
+			return
+		}
+		path, err := stealthKeyPath(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		key, err := keys.LoadKeys(path)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		symbol := args[4]
+		amount, err := parseAmount(*v, args[3], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.Send(*v, key, args[2], amount, symbol, args[5])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "propose-param":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: reward|difficulty|block-size value")
+			return
+		}
+		value, err := strconv.ParseUint(args[2], 10, 64)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := tran.NewProposeParamTransaction(address, args[1], value, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Proposal: %s\n", t.ID)
+	case "vote":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: proposal yes|no")
+			return
+		}
+		t, err := tran.NewVoteTransaction(address, args[1], args[2], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "proposals":
+		proposals, err := client.GetProposals(*v)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		for _, p := range proposals {
+			fmt.Printf("%s\t%s = %d\t%s\tvoting ends %d, activates %d\n", p.ID, p.Param, p.Value, p.State, p.VotingEnds, p.Activates)
+		}
+	case "proposal":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: proposal")
+			return
+		}
+		p, err := client.GetProposal(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Proposal: %s\n", p.ID)
+		fmt.Printf("Proposer: %s\n", p.Proposer)
+		fmt.Printf("Change: %s = %d\n", p.Param, p.Value)
+		fmt.Printf("Voting ends: %d\n", p.VotingEnds)
+		fmt.Printf("Activates: %d\n", p.Activates)
+		fmt.Printf("State: %s\n", p.State)
+		fmt.Printf("Votes: %d\n", len(p.Votes))
+		if p.State != "voting" {
+			fmt.Printf("Yes: %s RKY\n", formatAmount(*v, p.Yes, "RKY"))
+			fmt.Printf("No: %s RKY\n", formatAmount(*v, p.No, "RKY"))
+		}
+	case "params":
+		info, err := client.GetParams(*v)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Height: %d\n", info.Height)
+		fmt.Printf("Block version: %d\n", info.Params.BlockVersion)
+		fmt.Printf("Reward: %d %v\n", info.Params.Reward, info.Params.RewardSymbols)
+		fmt.Printf("Difficulty: %d\n", info.Params.Difficulty)
+		fmt.Printf("Max block size: %d\n", info.Params.MaxBlockSize)
+		for _, p := range info.Scheduled {
+			fmt.Printf("Scheduled: %s = %d at height %d (%s)\n", p.Param, p.Value, p.Activates, p.ID)
+		}
+	case "register-name":
+		if len(args) != 3 && len(args) != 4 {
+			fmt.Println("Requires arguments: name term-blocks [target]")
+			return
+		}
+		term, err := strconv.ParseUint(args[2], 10, 64)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		target := address
+		if len(args) == 4 {
+			target = args[3]
+		}
+		t, err := tran.NewRegisterNameTransaction(address, args[1], target, term, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "transfer-name":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: name new-owner")
+			return
+		}
+		t, err := tran.NewTransferNameTransaction(address, args[1], args[2], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "name":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: name")
+			return
+		}
+		r, err := client.GetName(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Name: %s\n", r.Name)
+		fmt.Printf("Address: %s\n", r.Target)
+		fmt.Printf("Owner: %s\n", r.Owner)
+		fmt.Printf("Expires: %d\n", r.Expires)
+	case "mint-nft":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: id metadata-file")
+			return
+		}
+		metadata, err := fileDigest(args[2])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := tran.NewMintNFTTransaction(address, args[1], metadata, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "send-nft":
+		if len(args) != 3 && len(args) != 4 {
+			fmt.Println("Requires arguments: dest id [memo]")
+			return
+		}
+		dest, err := client.ResolveAddress(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		memo := ""
+		if len(args) == 4 {
+			memo = args[3]
+		}
+		t, err := tran.NewTransferNFTTransaction(address, dest, args[2], memo, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "nfts":
+		if len(args) == 2 {
+			address, err = client.ResolveAddress(*v, args[1])
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
+		}
+		nfts, err := client.GetNFTs(*v, address)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		for _, n := range nfts {
+			fmt.Printf("%s\t%s\n", n.ID, n.Metadata)
+		}
+	case "nft":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: id")
+			return
+		}
+		n, err := client.GetNFT(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("ID: %s\n", n.ID)
+		fmt.Printf("Metadata: %s\n", n.Metadata)
+		fmt.Printf("Creator: %s\n", n.Creator)
+		fmt.Printf("Owner: %s\n", n.Owner)
+		fmt.Println("History:")
+		for _, h := range n.History {
+			if h.From == "" {
+				fmt.Printf("%d\t%s\tminted by %s\n", h.Height, h.Time.Format(time.RFC3339), h.To)
+			} else {
+				fmt.Printf("%d\t%s\t%s -> %s\n", h.Height, h.Time.Format(time.RFC3339), h.From, h.To)
+			}
+		}
+	case "buy", "sell":
+		if len(args) != 5 {
+			fmt.Println("Requires arguments: quantity base price quote")
+			return
+		}
+		base := args[2]
+		quote := args[4]
+		quantity, err := parseAmount(*v, args[1], base)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		price, err := parseAmount(*v, args[3], quote)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		p := tran.PlaceOrder{Base: base, Quote: quote, Side: command, Price: price, Quantity: quantity}
+		t, err := tran.NewPlaceOrderTransaction(address, p, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Order: %s\n", t.ID)
+	case "cancel-order":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: order")
+			return
+		}
+		t, err := tran.NewCancelOrderTransaction(address, args[1], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "orderbook":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: base quote")
+			return
+		}
+		book, err := client.GetOrderBook(*v, args[1], args[2])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Println("Asks:")
+		for i := len(book.Asks) - 1; i >= 0; i-- {
+			o := book.Asks[i]
+			fmt.Printf("%s %s @ %s %s\t%s\n", formatAmount(*v, o.Remaining, args[1]), args[1], formatAmount(*v, o.Price, args[2]), args[2], o.ID)
+		}
+		fmt.Println("Bids:")
+		for _, o := range book.Bids {
+			fmt.Printf("%s %s @ %s %s\t%s\n", formatAmount(*v, o.Remaining, args[1]), args[1], formatAmount(*v, o.Price, args[2]), args[2], o.ID)
+		}
+	case "trades":
+		if len(args) != 3 {
+			fmt.Println("Requires arguments: base quote")
+			return
+		}
+		trades, err := client.GetTrades(*v, args[1], args[2])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		for _, tr := range trades {
+			fmt.Printf("%d\t%s %s @ %s %s\n", tr.Height, formatAmount(*v, tr.Quantity, args[1]), args[1], formatAmount(*v, tr.Price, args[2]), args[2])
+		}
+	case "approve", "revoke":
+		if command == "approve" && len(args) != 4 {
+			fmt.Println("Requires arguments: spender limit symbol")
+			return
+		}
+		if command == "revoke" && len(args) != 3 {
+			fmt.Println("Requires arguments: spender symbol")
+			return
+		}
+		spender, err := client.ResolveAddress(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		symbol := args[len(args)-1]
+		var limit uint64
+		if command == "approve" {
+			limit, err = parseAmount(*v, args[2], symbol)
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
+		}
+		t, err := tran.NewApproveTransaction(address, spender, symbol, limit, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "allowances":
+		if len(args) == 2 {
+			address, err = client.ResolveAddress(*v, args[1])
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
+		}
+		allowances, err := client.GetAllowances(*v, address)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		for _, a := range allowances {
+			fmt.Printf("%s\t%s %s\n", a.Spender, formatAmount(*v, a.Limit, a.Symbol), a.Symbol)
+		}
+	case "spend-allowance":
+		if len(args) != 5 && len(args) != 6 {
+			fmt.Println("Requires arguments: owner dest amount symbol [memo]")
+			return
+		}
+		owner, err := client.ResolveAddress(*v, args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		dest, err := client.ResolveAddress(*v, args[2])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		symbol := args[4]
+		amount, err := parseAmount(*v, args[3], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		memo := ""
+		if len(args) == 6 {
+			memo = args[5]
+		}
+		t, err := tran.NewSpendAllowanceTransaction(symbol, address, owner, dest, amount, memo, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "notarize":
+		if len(args) != 2 && len(args) != 3 {
+			fmt.Println("Requires arguments: file [label]")
+			return
+		}
+		digest, err := fileDigest(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		label := ""
+		if len(args) == 3 {
+			label = args[2]
+		}
+		t, err := tran.NewAnchorTransaction(address, digest, label, time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.SignAndPost(*v, keyPair, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Anchored %s\n", hex.EncodeToString(digest))
+	case "verify":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: file")
+			return
+		}
+		digest, err := fileDigest(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		r, err := client.GetAnchor(*v, hex.EncodeToString(digest))
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Digest: %s\n", r.Digest)
+		if r.Label != "" {
+			fmt.Printf("Label: %s\n", r.Label)
+		}
+		fmt.Printf("Anchored by: %s\n", r.Source)
+		fmt.Printf("Block: %d\n", r.Height)
+		fmt.Printf("Time: %s\n", r.Time.Format(time.RFC3339))
+	case "timelock-address":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: unlock-height")
+			return
+		}
+		height, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		inner, err := script.PayToKey(&keyPair.PublicKey)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		lock := script.AfterHeight(height, inner)
+		fmt.Printf("Address: %s\n", keys.ScriptAddress(lock))
+		fmt.Printf("Script: %s\n", hex.EncodeToString(lock))
+	case "script-send":
+		// spends from a script address whose script is unlocked by a single
+		// signature from this wallet's key, such as a timelock address.
+		if len(args) != 6 {
+			fmt.Println("Requires arguments: script dest amount symbol memo")
+			return
+		}
+		lock, err := hex.DecodeString(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		symbol := args[4]
+		amount, err := parseAmount(*v, args[3], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := tran.NewTransaction(symbol, keys.ScriptAddress(lock), args[2], amount, args[5], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		sig, err := script.Sign(keyPair, t.SignatureDigest())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t.Witness = new(script.Builder).Push(sig).Script()
+		err = client.PostTransaction(*v, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	case "multisig":
+		if len(args) < 3 {
+			fmt.Println("Requires arguments: threshold address...")
+			return
+		}
+		threshold, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		pubs := []*ecdsa.PublicKey{}
+		for _, a := range args[2:] {
+			pub, err := keys.DecodeAddress(a)
+			if err != nil {
+				fmt.Printf("Error: %s\n", err)
+				return
+			}
+			pubs = append(pubs, pub)
+		}
+		m, err := keys.NewMultisig(threshold, pubs)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		multisigAddress, err := m.Address()
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Address: %s\n", multisigAddress)
+	case "propose":
+		if len(args) != 7 {
+			fmt.Println("Requires arguments: file source dest amount symbol memo")
+			return
+		}
+		symbol := args[5]
+		amount, err := parseAmount(*v, args[4], symbol)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		t, err := tran.NewTransaction(symbol, args[2], args[3], amount, args[6], time.Now().UTC())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = t.CosignTransaction(keyPair)
+		if err != nil && err != tran.ErrNotCosigner {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = writeTransaction(args[1], t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Proposed %s with %d signature(s)\n", t.ID, len(t.Signatures))
+	case "cosign":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: file")
+			return
+		}
+		t, err := readTransaction(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = t.CosignTransaction(keyPair)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = writeTransaction(args[1], t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		fmt.Printf("Signed %s, %d signature(s)\n", t.ID, len(t.Signatures))
+	case "broadcast":
+		if len(args) != 2 {
+			fmt.Println("Requires arguments: file")
+			return
+		}
+		t, err := readTransaction(args[1])
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+		err = client.PostTransaction(*v, t)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			return
+		}
+	default:
+		fmt.Println("Unknown command")
+	}
+}
+
+// parseAmount parses a decimal amount of symbol into base units, using the
+// token's decimals from the validator.
+func parseAmount(host string, amount string, symbol string) (uint64, error) {
+	tk, err := client.GetToken(host, symbol)
+	if err != nil {
+		return 0, err
+	}
+	return tk.Parse(amount)
+}
+
+// formatAmount formats base units of symbol, falling back to the raw units if
+// the token can't be fetched.
+func formatAmount(host string, amount uint64, symbol string) string {
+	tk, err := client.GetToken(host, symbol)
+	if err != nil {
+		return fmt.Sprintf("%d", amount)
+	}
+	return tk.Format(amount)
+}
+
+// readOutputs reads the payments of a batch transfer from a CSV file with one
+// "dest,amount,symbol" row per payment.
+func readOutputs(host string, file string) ([]tran.Output, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 3
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[string]token.Token)
+	outputs := make([]tran.Output, 0, len(records))
+	for i, rec := range records {
+		symbol := rec[2]
+		tk, ok := tokens[symbol]
+		if !ok {
+			tk, err = client.GetToken(host, symbol)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", i+1, err)
+			}
+			tokens[symbol] = tk
+		}
+		amount, err := tk.Parse(rec[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		dest, err := client.ResolveAddress(host, rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		outputs = append(outputs, tran.Output{Destination: dest, Symbol: symbol, Amount: amount})
+	}
+	return outputs, nil
+}
+
+// fileDigest returns the SHA-256 digest of a file's contents.
+func fileDigest(file string) ([]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// readTransaction loads a partially signed transaction from file. Its ID is
+// recalculated so a proposal edited after it was written can't be cosigned.
+func readTransaction(file string) (tran.Transaction, error) {
+	var t tran.Transaction
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return t, err
+	}
+
+	id := t.ID
+	if err := t.CalculateID(); err != nil {
+		return t, err
+	}
+	if id != t.ID {
+		return t, fmt.Errorf("transaction in %s does not match its id", file)
+	}
+	return t, nil
+}
+
+// writeTransaction saves a partially signed transaction to file for the next
+// cosigner.
+func writeTransaction(file string, t tran.Transaction) error {
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
+
+// loadChannel returns the saved state of a channel, or a fresh view of it
+// built from the validator if this wallet has not seen it before.
+func loadChannel(host string, id string) (*channel.Channel, error) {
+	path, err := channel.GetDefaultPath(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return channel.Load(path)
+	}
+
+	c, err := client.GetChannel(host, id)
+	if err != nil {
+		return nil, err
+	}
+	return channel.New(c.ID, c.Sender, c.Recipient, c.Symbol, c.Capacity), nil
+}
+
+// saveChannel saves the state of a channel, including its latest update.
+func saveChannel(c *channel.Channel) error {
+	path, err := channel.GetDefaultPath(c.ID)
+	if err != nil {
+		return err
+	}
+	return c.Save(path)
+}
+
+// loadStealthKeys loads the wallet's stealth keys, generating them next to
+// its key pair if needed.
+func loadStealthKeys() (*keys.StealthKeys, error) {
+	path, err := keys.GetDefaultKeyPath()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	return keys.LoadOrGenerateStealthKeys(filepath.Join(dir, "stealth-scan.pem"), filepath.Join(dir, "stealth-spend.pem"))
+}
+
+// stealthKeyPath returns where the key of the one-time address paid by a
+// stealth transfer is saved.
+func stealthKeyPath(id string) (string, error) {
+	path, err := keys.GetDefaultKeyPath()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(filepath.Dir(path), "stealth")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
 	}
+	return filepath.Join(dir, id+".pem"), nil
 }
@@ -1,16 +1,19 @@
 package block
 
 import (
+	"crypto/ecdsa"
+	"crypto/rand"
 	"crypto/sha256"
-	"encoding/json"
-	"strconv"
+	"math/big"
 	"time"
 
+	"github.com/datravis/lolachain/pkg/keys"
 	"github.com/datravis/lolachain/pkg/tran"
 )
 
 // Block is an entry on the blockchain.
 type Block struct {
+	Version      uint8              `json:"version"`
 	Index        uint64             `json:"index"`
 	Time         time.Time          `json:"time"`
 	Transactions []tran.Transaction `json:"transactions"`
@@ -18,11 +21,14 @@ type Block struct {
 	PreviousHash [32]byte           `json:"previous_hash"`
 	Hash         [32]byte           `json:"hash"`
 	Incrementor  uint64             `json:"incrementor"`
+	R            *big.Int           `json:"r,omitempty"`
+	S            *big.Int           `json:"s,omitempty"`
 }
 
 // NewBlock returns an instance of a Block based on the supplied parameters.
-func NewBlock(index uint64, t time.Time, transactions []tran.Transaction, validator string, previousHash [32]byte, incrementor uint64) (*Block, error) {
+func NewBlock(version uint8, index uint64, t time.Time, transactions []tran.Transaction, validator string, previousHash [32]byte, incrementor uint64) (*Block, error) {
 	block := &Block{
+		Version:      version,
 		Index:        index,
 		Time:         t,
 		Transactions: transactions,
@@ -36,23 +42,43 @@ func NewBlock(index uint64, t time.Time, transactions []tran.Transaction, valida
 	return block, err
 }
 
-// CalculateHash computes a blocks hash.
+// CalculateHash computes a blocks hash, the SHA-256 digest of its canonical
+// header encoding.
 func (b *Block) CalculateHash() ([32]byte, error) {
-	indexBytes := []byte(strconv.FormatUint(b.Index, 10))
-	timeBytes := []byte(b.Time.UTC().Format(time.RFC3339))
-	incrementorBytes := []byte(strconv.FormatUint(b.Incrementor, 10))
-
-	transjson, err := json.Marshal(b.Transactions)
+	header, err := b.HeaderBytes()
 	if err != nil {
 		return [32]byte{}, err
 	}
+	return sha256.Sum256(header), nil
+}
+
+// SignBlock signs a block's hash with the validator's private key. The
+// signature is normalized to low-S form.
+func (b *Block) SignBlock(key *ecdsa.PrivateKey) (*big.Int, *big.Int, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, key, b.Hash[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	s = keys.NormalizeS(&key.PublicKey, s)
+	b.R = r
+	b.S = s
 
-	toHash := []byte{}
-	toHash = append(toHash, indexBytes...)
-	toHash = append(toHash, timeBytes...)
-	toHash = append(toHash, transjson...)
-	toHash = append(toHash, b.PreviousHash[:]...)
-	toHash = append(toHash, incrementorBytes...)
+	return r, s, nil
+}
 
-	return sha256.Sum256(toHash), nil
+// VerifyBlock verifies a block was signed by its validator. The signature
+// covers the block hash, so it is only meaningful once the hash is checked.
+func (b *Block) VerifyBlock() (bool, error) {
+	if b.R == nil || b.S == nil {
+		return false, nil
+	}
+
+	publicKey, err := keys.DecodeAddress(b.Validator)
+	if err != nil {
+		return false, err
+	}
+	if !keys.IsLowS(publicKey, b.S) {
+		return false, nil
+	}
+	return ecdsa.Verify(publicKey, b.Hash[:], b.R, b.S), nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/datravis/lolachain/pkg/keys"
 	"github.com/datravis/lolachain/pkg/tran"
 
 	"github.com/stretchr/testify/assert"
@@ -16,8 +17,9 @@ func TestNewBlock(t *testing.T) {
 	tr := []tran.Transaction{}
 	address := "my_addr"
 	previousHash := [32]byte{}
+	incrementor := uint64(0)
 
-	b, err := NewBlock(index, tm, tr, address, previousHash)
+	b, err := NewBlock(Version1, index, tm, tr, address, previousHash, incrementor)
 	assert.Nil(t, err)
 
 	if assert.NotNil(t, b) {
@@ -31,14 +33,15 @@ func TestNewBlock(t *testing.T) {
 
 // TestCalculateHash verifies the CalculateHash method returns the expected hash.
 func TestCalculateHash(t *testing.T) {
-	hash := [32]uint8{0x91, 0x46, 0xfb, 0xfb, 0x28, 0xaf, 0x8e, 0xbf, 0x8b, 0x7c, 0x71, 0x3e, 0x77, 0x6e, 0x22, 0x72, 0x13, 0x8c, 0x8a, 0xba, 0x92, 0x74, 0xb, 0x62, 0xee, 0xd1, 0x7c, 0x6b, 0x2f, 0x5d, 0x61, 0x64}
+	hash := [32]uint8{0x8e, 0x8e, 0xb4, 0x6b, 0xee, 0xd0, 0x48, 0x74, 0xef, 0x78, 0x0, 0x70, 0xe8, 0x81, 0x32, 0xcb, 0xc8, 0x8b, 0xdb, 0xd3, 0x4f, 0x4e, 0x7e, 0x58, 0x82, 0x46, 0x56, 0x9f, 0x91, 0x3b, 0xd8, 0x5}
 	index := uint64(0)
 	tm := time.Unix(0, 0)
 	tr := []tran.Transaction{}
 	address := "my_addr"
 	previousHash := [32]byte{}
+	incrementor := uint64(0)
 
-	b, err := NewBlock(index, tm, tr, address, previousHash)
+	b, err := NewBlock(Version1, index, tm, tr, address, previousHash, incrementor)
 	assert.Nil(t, err)
 
 	h, err := b.CalculateHash()
@@ -49,3 +52,41 @@ func TestCalculateHash(t *testing.T) {
 		assert.Equal(t, hash, b.Hash)
 	}
 }
+
+// TestSignAndVerify tests whether we can sign blocks and verify their validator.
+func TestSignAndVerify(t *testing.T) {
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+
+	validator, err := keys.GetAddress(k)
+	assert.Nil(t, err)
+
+	b, err := NewBlock(Version1, 0, time.Unix(0, 0).UTC(), []tran.Transaction{}, validator, [32]byte{}, 0)
+	assert.Nil(t, err)
+
+	ok, err := b.VerifyBlock()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	_, _, err = b.SignBlock(k)
+	assert.Nil(t, err)
+
+	ok, err = b.VerifyBlock()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// relabelling the validator changes the hash and breaks the signature.
+	other, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	b.Validator, err = keys.GetAddress(other)
+	assert.Nil(t, err)
+
+	h, err := b.CalculateHash()
+	assert.Nil(t, err)
+	assert.NotEqual(t, b.Hash, h)
+	b.Hash = h
+
+	ok, err = b.VerifyBlock()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
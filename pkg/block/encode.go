@@ -0,0 +1,69 @@
+package block
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// Block versions. The version selects the rules a block is built with,
+// starting with how its header is hashed.
+const (
+	// Version1 headers use version 1 of the canonical encoding.
+	Version1 = 1
+
+	// CurrentVersion is the newest block version this code understands.
+	CurrentVersion = Version1
+)
+
+// HeaderBytes returns the canonical encoding of the block header. The header
+// commits to the transactions through TransactionsHash, and its leading
+// version byte is the block version.
+func (b *Block) HeaderBytes() ([]byte, error) {
+	if b.Version == 0 || b.Version > CurrentVersion {
+		return nil, fmt.Errorf("unknown block version %d", b.Version)
+	}
+	txHash := b.TransactionsHash()
+
+	e := codec.NewEncoder(b.Version, codec.KindBlockHeader)
+	e.Uint64(b.Index)
+	e.Time(b.Time)
+	e.Fixed(txHash[:])
+	e.String(b.Validator)
+	e.Fixed(b.PreviousHash[:])
+	e.Uint64(b.Incrementor)
+	return e.Bytes(), nil
+}
+
+// TransactionsHash returns the SHA-256 digest of the transaction count
+// followed by the digest of each transaction's full encoding, signature
+// included.
+func (b *Block) TransactionsHash() [32]byte {
+	e := codec.NewEncoder(codec.Version, codec.KindTransactions)
+	e.Uint64(uint64(len(b.Transactions)))
+	for _, t := range b.Transactions {
+		sum := sha256.Sum256(t.Encode())
+		e.Fixed(sum[:])
+	}
+	return sha256.Sum256(e.Bytes())
+}
+
+// Size returns the encoded size of the block in bytes: its header, every
+// transaction and the validator's signature.
+func (b *Block) Size() (int, error) {
+	header, err := b.HeaderBytes()
+	if err != nil {
+		return 0, err
+	}
+
+	e := codec.NewEncoder(codec.Version, codec.KindSignature)
+	e.BigInt(b.R)
+	e.BigInt(b.S)
+
+	size := len(header) + len(e.Bytes())
+	for _, t := range b.Transactions {
+		size += len(t.Encode())
+	}
+	return size, nil
+}
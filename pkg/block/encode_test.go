@@ -0,0 +1,56 @@
+package block
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHeaderBytesVector checks the canonical header encoding against a hand assembled test vector.
+func TestHeaderBytesVector(t *testing.T) {
+	b, err := NewBlock(Version1, 0, time.Unix(0, 0), []tran.Transaction{}, "my_addr", [32]byte{}, 0)
+	assert.Nil(t, err)
+
+	expected := "01" + "42" + // version, kind
+		"0000000000000000" + // index
+		"0000000000000000" + "00000000" + // time
+		"c35bf5ec655dfd6363fedd4998085018582f317b3a0c9b3e19e75de0055d54cc" + // transactions hash
+		"00000007" + "6d795f61646472" + // validator
+		"0000000000000000000000000000000000000000000000000000000000000000" + // previous hash
+		"0000000000000000" // incrementor
+	header, err := b.HeaderBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, hex.EncodeToString(header))
+	assert.Equal(t, "8e8eb46beed04874ef780070e88132cbc88bdbd34f4e7e588246569f913bd805", hex.EncodeToString(b.Hash[:]))
+}
+
+// TestHashCoversTransactions verifies changing a transaction or its signature changes the block hash.
+func TestHashCoversTransactions(t *testing.T) {
+	tr, err := tran.NewTransaction("TEST", "source_address", "dest_address", 1, "memo", time.Unix(0, 0))
+	assert.Nil(t, err)
+	tr.R = big.NewInt(1)
+	tr.S = big.NewInt(2)
+
+	b, err := NewBlock(Version1, 1, time.Unix(60, 0), []tran.Transaction{tr}, "my_addr", [32]byte{}, 0)
+	assert.Nil(t, err)
+
+	b.Transactions[0].Memo = "other"
+	h, err := b.CalculateHash()
+	assert.Nil(t, err)
+	assert.NotEqual(t, b.Hash, h)
+
+	b.Transactions[0] = tr
+	h, err = b.CalculateHash()
+	assert.Nil(t, err)
+	assert.Equal(t, b.Hash, h)
+
+	b.Transactions[0].S = big.NewInt(3)
+	h, err = b.CalculateHash()
+	assert.Nil(t, err)
+	assert.NotEqual(t, b.Hash, h)
+}
@@ -0,0 +1,138 @@
+package block
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxBlockSize is the largest encoded size, in bytes, of a block under
+	// the initial consensus rules.
+	MaxBlockSize = 1 << 20
+
+	// MaxFutureDrift is how far past the local clock a block may be
+	// timestamped.
+	MaxFutureDrift = 2 * time.Minute
+
+	// MedianTimeSpan is the number of preceding blocks used to compute the
+	// median time past.
+	MedianTimeSpan = 11
+)
+
+// Errors returned by block validation, wrapped in a *ValidationError.
+var (
+	ErrBadVersion           = errors.New("unexpected block version")
+	ErrBadIndex             = errors.New("index does not follow parent")
+	ErrBadPreviousHash      = errors.New("previous hash does not match parent")
+	ErrTimeTooOld           = errors.New("time is not after parent and median time past")
+	ErrTimeTooNew           = errors.New("time too far in the future")
+	ErrBadHash              = errors.New("hash does not match contents")
+	ErrBadSignature         = errors.New("not signed by validator")
+	ErrBadIncrementor       = errors.New("incrementor does not meet the difficulty")
+	ErrBlockTooLarge        = errors.New("block too large")
+	ErrDuplicateTransaction = errors.New("duplicate transaction")
+)
+
+// ValidationError reports which rule a block failed.
+type ValidationError struct {
+	Index uint64
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Block invalid: %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the rule that failed, for use with errors.Is.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// MedianTimePast returns the median time of the last MedianTimeSpan blocks.
+func MedianTimePast(blocks []*Block) time.Time {
+	if len(blocks) > MedianTimeSpan {
+		blocks = blocks[len(blocks)-MedianTimeSpan:]
+	}
+	if len(blocks) == 0 {
+		return time.Time{}
+	}
+
+	times := make([]time.Time, len(blocks))
+	for i, b := range blocks {
+		times[i] = b.Time
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	return times[len(times)/2]
+}
+
+// ValidateHeader checks a block's header against its parent, the median time
+// past of the preceding blocks and the local clock. The parent is nil for the
+// genesis block.
+func (b *Block) ValidateHeader(parent *Block, medianTimePast time.Time, now time.Time) error {
+	invalid := func(err error) error {
+		return &ValidationError{Index: b.Index, Err: err}
+	}
+
+	if parent == nil {
+		if b.Index != 0 {
+			return invalid(ErrBadIndex)
+		}
+		if b.PreviousHash != [32]byte{} {
+			return invalid(ErrBadPreviousHash)
+		}
+	} else {
+		if b.Index != parent.Index+1 {
+			return invalid(ErrBadIndex)
+		}
+		if b.PreviousHash != parent.Hash {
+			return invalid(ErrBadPreviousHash)
+		}
+		if !b.Time.After(parent.Time) || !b.Time.After(medianTimePast) {
+			return invalid(ErrTimeTooOld)
+		}
+	}
+
+	if b.Time.After(now.Add(MaxFutureDrift)) {
+		return invalid(ErrTimeTooNew)
+	}
+
+	hash, err := b.CalculateHash()
+	if err != nil {
+		return invalid(ErrBadVersion)
+	}
+	if hash != b.Hash {
+		return invalid(ErrBadHash)
+	}
+
+	return nil
+}
+
+// ValidateBody checks the rules a block's contents must follow regardless of
+// chain state. The block may be at most maxSize bytes once encoded.
+func (b *Block) ValidateBody(maxSize uint64) error {
+	invalid := func(err error) error {
+		return &ValidationError{Index: b.Index, Err: err}
+	}
+
+	size, err := b.Size()
+	if err != nil {
+		return err
+	}
+	if uint64(size) > maxSize {
+		return invalid(ErrBlockTooLarge)
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range b.Transactions {
+		hash := t.CalculateWitnessHash()
+		if seen[hash] {
+			return invalid(ErrDuplicateTransaction)
+		}
+		seen[hash] = true
+	}
+
+	return nil
+}
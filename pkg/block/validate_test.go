@@ -0,0 +1,87 @@
+package block
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testChain(t *testing.T, n int) []*Block {
+	blocks := []*Block{}
+	previousHash := [32]byte{}
+	for i := 0; i < n; i++ {
+		b, err := NewBlock(Version1, uint64(i), time.Unix(int64(i*60), 0).UTC(), []tran.Transaction{}, "my_addr", previousHash, 0)
+		assert.Nil(t, err)
+		blocks = append(blocks, b)
+		previousHash = b.Hash
+	}
+	return blocks
+}
+
+// TestMedianTimePast verifies the median is taken over the most recent blocks.
+func TestMedianTimePast(t *testing.T) {
+	assert.Equal(t, time.Time{}, MedianTimePast(nil))
+
+	blocks := testChain(t, 20)
+	assert.Equal(t, blocks[14].Time, MedianTimePast(blocks))
+	assert.Equal(t, blocks[1].Time, MedianTimePast(blocks[:3]))
+}
+
+// TestValidateHeader verifies the header rules against a parent block.
+func TestValidateHeader(t *testing.T) {
+	blocks := testChain(t, 3)
+	now := blocks[2].Time
+	mtp := MedianTimePast(blocks[:2])
+
+	assert.Nil(t, blocks[0].ValidateHeader(nil, time.Time{}, now))
+	assert.Nil(t, blocks[2].ValidateHeader(blocks[1], mtp, now))
+
+	cases := []struct {
+		modify func(*Block)
+		err    error
+	}{
+		{func(b *Block) { b.Version = CurrentVersion + 1 }, ErrBadVersion},
+		{func(b *Block) { b.Index = 5 }, ErrBadIndex},
+		{func(b *Block) { b.PreviousHash = [32]byte{1} }, ErrBadPreviousHash},
+		{func(b *Block) { b.Time = blocks[1].Time }, ErrTimeTooOld},
+		{func(b *Block) { b.Time = now.Add(MaxFutureDrift + time.Second) }, ErrTimeTooNew},
+	}
+	for _, c := range cases {
+		b := *blocks[2]
+		c.modify(&b)
+		b.Hash, _ = b.CalculateHash()
+
+		err := b.ValidateHeader(blocks[1], mtp, now)
+		assert.True(t, errors.Is(err, c.err), "expected %s, got %v", c.err, err)
+	}
+
+	b := *blocks[2]
+	b.Incrementor++
+	err := b.ValidateHeader(blocks[1], mtp, now)
+	assert.True(t, errors.Is(err, ErrBadHash))
+}
+
+// TestValidateBody verifies duplicate transactions and oversized blocks are
+// rejected.
+func TestValidateBody(t *testing.T) {
+	tr, err := tran.NewTransaction("TEST", "source_address", "dest_address", 1, "memo", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+
+	b, err := NewBlock(Version1, 1, time.Unix(60, 0).UTC(), []tran.Transaction{tr}, "my_addr", [32]byte{}, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, b.ValidateBody(MaxBlockSize))
+
+	size, err := b.Size()
+	assert.Nil(t, err)
+	assert.Nil(t, b.ValidateBody(uint64(size)))
+	err = b.ValidateBody(uint64(size) - 1)
+	assert.True(t, errors.Is(err, ErrBlockTooLarge))
+
+	b.Transactions = append(b.Transactions, tr)
+	err = b.ValidateBody(MaxBlockSize)
+	assert.True(t, errors.Is(err, ErrDuplicateTransaction))
+}
@@ -0,0 +1,116 @@
+package chain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+func init() {
+	RegisterHandler(tran.TypeApprove, approveHandler{})
+	RegisterHandler(tran.TypeSpendAllowance, spendAllowanceHandler{})
+}
+
+// Allowance is how much of a symbol a spender may still move out of an
+// owner's balance, in base units.
+type Allowance struct {
+	Spender string `json:"spender"`
+	Symbol  string `json:"symbol"`
+	Limit   uint64 `json:"limit"`
+}
+
+// Allowance returns how much of symbol spender may still move from owner.
+func (s *State) Allowance(owner, spender, symbol string) uint64 {
+	return s.allowances[owner][spender][symbol]
+}
+
+// Allowances returns every allowance granted by owner, sorted by spender and
+// symbol.
+func (s *State) Allowances(owner string) []Allowance {
+	allowances := []Allowance{}
+	for spender, limits := range s.allowances[owner] {
+		for symbol, limit := range limits {
+			allowances = append(allowances, Allowance{Spender: spender, Symbol: symbol, Limit: limit})
+		}
+	}
+	sort.Slice(allowances, func(i, j int) bool {
+		if allowances[i].Spender != allowances[j].Spender {
+			return allowances[i].Spender < allowances[j].Spender
+		}
+		return allowances[i].Symbol < allowances[j].Symbol
+	})
+	return allowances
+}
+
+func (s *State) setAllowance(owner, spender, symbol string, limit uint64) {
+	if limit == 0 {
+		delete(s.allowances[owner][spender], symbol)
+		if len(s.allowances[owner][spender]) == 0 {
+			delete(s.allowances[owner], spender)
+		}
+		if len(s.allowances[owner]) == 0 {
+			delete(s.allowances, owner)
+		}
+		return
+	}
+	if _, ok := s.allowances[owner]; !ok {
+		s.allowances[owner] = make(map[string]map[string]uint64)
+	}
+	if _, ok := s.allowances[owner][spender]; !ok {
+		s.allowances[owner][spender] = make(map[string]uint64)
+	}
+	s.allowances[owner][spender][symbol] = limit
+}
+
+// approveHandler sets or revokes an allowance granted by the sender.
+type approveHandler struct{}
+
+func (approveHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	approve := p.(*tran.Approve)
+	if _, ok := s.tokens[approve.Symbol]; !ok {
+		return fmt.Errorf("Transaction invalid: %s: unknown symbol %s", t.ID, approve.Symbol)
+	}
+	if approve.Spender == t.Source {
+		return fmt.Errorf("Transaction invalid: %s: an address can't approve itself", t.ID)
+	}
+	return nil
+}
+
+func (approveHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	approve := p.(*tran.Approve)
+	s.setAllowance(t.Source, approve.Spender, approve.Symbol, approve.Limit)
+}
+
+// spendAllowanceHandler moves funds from an owner on the sender's behalf,
+// within the allowance the owner granted it.
+type spendAllowanceHandler struct{}
+
+func (spendAllowanceHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	owner := p.(*tran.SpendAllowance).Owner
+	if s.Allowance(owner, t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Transaction invalid: %s: amount exceeds allowance", t.ID)
+	}
+	if s.Balance(owner, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	return nil
+}
+
+func (spendAllowanceHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	owner := p.(*tran.SpendAllowance).Owner
+
+	s.setAllowance(owner, t.Source, t.Symbol, s.Allowance(owner, t.Source, t.Symbol)-t.Amount)
+	s.Debit(owner, t.Symbol, t.Amount)
+	s.Credit(t.Destination, t.Symbol, t.Amount)
+}
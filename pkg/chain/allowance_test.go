@@ -0,0 +1,47 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllowance verifies a spender can move an owner's funds up to the
+// approved limit, and not after the allowance is revoked.
+func TestAllowance(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	state := NewState()
+	state.Credit("alice", "RKY", 10)
+
+	approve, err := tran.NewApproveTransaction("alice", "shop", "RKY", 6, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(approve, Context{}))
+	assert.Equal(t, []Allowance{{Spender: "shop", Symbol: "RKY", Limit: 6}}, state.Allowances("alice"))
+
+	pull, err := tran.NewSpendAllowanceTransaction("RKY", "shop", "alice", "shop", 4, "subscription", tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(pull, Context{}))
+	assert.Equal(t, uint64(6), state.Balance("alice", "RKY"))
+	assert.Equal(t, uint64(4), state.Balance("shop", "RKY"))
+	assert.Equal(t, uint64(2), state.Allowance("alice", "shop", "RKY"))
+
+	tooMuch, err := tran.NewSpendAllowanceTransaction("RKY", "shop", "alice", "shop", 3, "", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(tooMuch, Context{}))
+
+	stranger, err := tran.NewSpendAllowanceTransaction("RKY", "mallory", "alice", "mallory", 1, "", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(stranger, Context{}))
+
+	revoke, err := tran.NewApproveTransaction("alice", "shop", "RKY", 0, tm.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(revoke, Context{}))
+	assert.Equal(t, []Allowance{}, state.Allowances("alice"))
+
+	last, err := tran.NewSpendAllowanceTransaction("RKY", "shop", "alice", "shop", 1, "", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(last, Context{}))
+}
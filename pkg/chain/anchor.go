@@ -0,0 +1,61 @@
+package chain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+func init() {
+	RegisterHandler(tran.TypeAnchor, anchorHandler{})
+}
+
+// AnchorRecord proves a digest was anchored by the block at Height, whose
+// timestamp is Time.
+type AnchorRecord struct {
+	Digest      string    `json:"digest"`
+	Label       string    `json:"label,omitempty"`
+	Source      string    `json:"source"`
+	Transaction string    `json:"transaction"`
+	Height      uint64    `json:"height"`
+	Time        time.Time `json:"time"`
+}
+
+// Anchor returns the earliest anchor of a hex encoded digest.
+func (s *State) Anchor(digest string) (AnchorRecord, bool) {
+	r, ok := s.anchors[digest]
+	return r, ok
+}
+
+// anchorHandler records a digest. Only the first anchor of a digest is
+// kept, since it is the one that proves when the document existed.
+type anchorHandler struct{}
+
+func (anchorHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	digest := hex.EncodeToString(p.(*tran.Anchor).Digest)
+	if r, ok := s.anchors[digest]; ok {
+		return fmt.Errorf("Transaction invalid: %s: digest already anchored in block %d", t.ID, r.Height)
+	}
+	return nil
+}
+
+func (anchorHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	anchor := p.(*tran.Anchor)
+
+	digest := hex.EncodeToString(anchor.Digest)
+	s.anchors[digest] = AnchorRecord{
+		Digest:      digest,
+		Label:       anchor.Label,
+		Source:      t.Source,
+		Transaction: t.ID,
+		Height:      ctx.Height,
+		Time:        ctx.Time,
+	}
+}
@@ -0,0 +1,36 @@
+package chain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/block"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnchor verifies an anchored digest records the block that included it,
+// and can't be anchored again later.
+func TestAnchor(t *testing.T) {
+	tm := time.Unix(1000, 0).UTC()
+	digest := sha256.Sum256([]byte("contract.pdf"))
+
+	anchor, err := tran.NewAnchorTransaction("alice", digest[:], "contract", tm)
+	assert.Nil(t, err)
+	state := NewState()
+	assert.Nil(t, state.ApplyBlock(&block.Block{Index: 7, Time: tm, Validator: "alice", Transactions: []tran.Transaction{anchor}}))
+
+	r, ok := state.Anchor(hex.EncodeToString(digest[:]))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(7), r.Height)
+	assert.Equal(t, tm, r.Time)
+	assert.Equal(t, "contract", r.Label)
+	assert.Equal(t, anchor.ID, r.Transaction)
+
+	again, err := tran.NewAnchorTransaction("bob", digest[:], "", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(again, Context{Height: 8}))
+}
@@ -0,0 +1,43 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+func init() {
+	RegisterHandler(tran.TypeBatch, batchHandler{})
+}
+
+// batchHandler pays every output of a batch from the source. The source's
+// balance is checked once against the total of each symbol.
+type batchHandler struct{}
+
+func (batchHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	totals, ok := p.(*tran.Batch).Totals()
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: batch total overflows", t.ID)
+	}
+	for symbol, total := range totals {
+		if _, ok := s.tokens[symbol]; !ok {
+			return fmt.Errorf("Transaction invalid: %s: unknown symbol %s", t.ID, symbol)
+		}
+		if s.Balance(t.Source, symbol) < total {
+			return fmt.Errorf("Insufficient funds to perform transaction")
+		}
+	}
+	return nil
+}
+
+func (batchHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	for _, o := range p.(*tran.Batch).Outputs {
+		s.Debit(t.Source, o.Symbol, o.Amount)
+		s.Credit(o.Destination, o.Symbol, o.Amount)
+	}
+}
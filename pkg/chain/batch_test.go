@@ -0,0 +1,37 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatch verifies a batch pays every output, or none if the sender can't
+// cover all of them.
+func TestBatch(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	state := NewState()
+	state.Credit("alice", "RKY", 10)
+	state.Credit("alice", "LOLA", 3)
+
+	tooMuch, err := tran.NewBatchTransaction("alice", []tran.Output{{Destination: "bob", Symbol: "RKY", Amount: 6}, {Destination: "carol", Symbol: "RKY", Amount: 6}}, "", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.ApplyTransaction(tooMuch, Context{}))
+	assert.Equal(t, uint64(10), state.Balance("alice", "RKY"))
+
+	unknown, err := tran.NewBatchTransaction("alice", []tran.Output{{Destination: "bob", Symbol: "DOG", Amount: 1}}, "", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(unknown, Context{}))
+
+	payroll, err := tran.NewBatchTransaction("alice", []tran.Output{{Destination: "bob", Symbol: "RKY", Amount: 4}, {Destination: "carol", Symbol: "RKY", Amount: 6}, {Destination: "carol", Symbol: "LOLA", Amount: 3}}, "payroll", tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(payroll, Context{}))
+	assert.Equal(t, uint64(0), state.Balance("alice", "RKY"))
+	assert.Equal(t, uint64(0), state.Balance("alice", "LOLA"))
+	assert.Equal(t, uint64(4), state.Balance("bob", "RKY"))
+	assert.Equal(t, uint64(6), state.Balance("carol", "RKY"))
+	assert.Equal(t, uint64(3), state.Balance("carol", "LOLA"))
+}
@@ -96,6 +96,14 @@ func (c *Chain) FindPeers() {
 
 // PostTransaction posts a transaction the transaction mempool.
 func (c *Chain) PostTransaction(t tran.Transaction) error {
+	if t.IsCoinbase() {
+		return fmt.Errorf("Transaction invalid: %s: coinbase transactions are only valid as a block reward", t.ID)
+	}
+
+	if err := t.Validate(time.Now().UTC()); err != nil {
+		return err
+	}
+
 	ok, err := t.VerifyTransaction()
 	if err != nil {
 		return err
@@ -112,6 +120,13 @@ func (c *Chain) PostTransaction(t tran.Transaction) error {
 		return fmt.Errorf("Transaction invalid: %s", t.ID)
 	}
 
+	hash := t.CalculateWitnessHash()
+	for _, p := range c.Pending {
+		if p.CalculateWitnessHash() == hash {
+			return fmt.Errorf("Transaction already pending: %s", t.ID)
+		}
+	}
+
 	c.Pending = append(c.Pending, t)
 	return nil
 }
@@ -121,26 +136,34 @@ func (c *Chain) NextBlock(transactions []tran.Transaction, incrementor uint64, k
 	lastBlock := c.Blocks[len(c.Blocks)-1]
 	ts := time.Now().UTC()
 
-	reward, err := c.CreateRewardTransaction(ts, "RKY", keyPair)
+	validatorAddress, err := keys.GetAddress(keyPair)
 	if err != nil {
 		return nil, err
 	}
-	transactions = append(transactions, reward)
 
-	reward, err = c.CreateRewardTransaction(ts, "LOLA", keyPair)
-	if err != nil {
-		return nil, err
-	}
-	transactions = append(transactions, reward)
+	params := c.State().Params(lastBlock.Index + 1)
+	ctx := Context{Height: lastBlock.Index + 1, Time: ts, Validator: validatorAddress}
+	validTransactions := c.ValidateTransactions(transactions, params, ctx)
 
-	validTransactions := c.ValidateTransactions(transactions)
+	for _, symbol := range params.RewardSymbols {
+		amount, _ := params.BlockReward(symbol)
+		// governance may set the reward to zero, and a coinbase must mint
+		// something.
+		if amount == 0 {
+			continue
+		}
+		reward, err := c.CreateRewardTransaction(ts, symbol, amount, keyPair)
+		if err != nil {
+			return nil, err
+		}
+		validTransactions = append(validTransactions, reward)
+	}
 
-	validatorAddress, err := keys.GetAddress(keyPair)
+	nextBlock, err := block.NewBlock(params.BlockVersion, lastBlock.Index+1, ts, validTransactions, validatorAddress, lastBlock.Hash, incrementor)
 	if err != nil {
 		return nil, err
 	}
-
-	nextBlock, err := block.NewBlock(lastBlock.Index+1, ts, validTransactions, validatorAddress, lastBlock.Hash, incrementor)
+	_, _, err = nextBlock.SignBlock(keyPair)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +180,11 @@ func (c *Chain) GenesisBlock(keyPair *ecdsa.PrivateKey) (*block.Block, error) {
 	}
 
 	ts := time.Now().UTC()
-	nextBlock, err := block.NewBlock(0, ts, []tran.Transaction{}, validatorAddress, [32]byte{}, INCREMENTOR_DIVISOR)
+	nextBlock, err := block.NewBlock(ParamsAt(0).BlockVersion, 0, ts, []tran.Transaction{}, validatorAddress, [32]byte{}, INCREMENTOR_DIVISOR)
+	if err != nil {
+		return nil, err
+	}
+	_, _, err = nextBlock.SignBlock(keyPair)
 	if err != nil {
 		return nil, err
 	}
@@ -166,22 +193,41 @@ func (c *Chain) GenesisBlock(keyPair *ecdsa.PrivateKey) (*block.Block, error) {
 	return nextBlock, nil
 }
 
-// ValidateTransactions validates the list of provided transactions.
-func (c *Chain) ValidateTransactions(trans []tran.Transaction) []tran.Transaction {
+// ValidateTransactions validates the list of provided transactions for
+// inclusion in the block described by ctx under params. Coinbase transactions
+// are dropped, block rewards are added by NextBlock.
+func (c *Chain) ValidateTransactions(trans []tran.Transaction, params Params, ctx Context) []tran.Transaction {
+	state := c.State()
+	state.Unlock(ctx)
 	batch := []tran.Transaction{}
 	for _, t := range trans {
-		if t.Memo != "block reward" {
-			ok, err := c.VerifyBalance(t)
-			if err != nil || !ok {
-				fmt.Printf("transaction invalid: %s\n", err)
-				continue
-			}
+		if t.IsCoinbase() {
+			fmt.Printf("transaction invalid: %s: unexpected coinbase\n", t.ID)
+			continue
+		}
+		if !params.AllowsType(t.Type) {
+			fmt.Printf("transaction invalid: %s: type %s is not active\n", t.ID, t.Type)
+			continue
+		}
+		if !params.AllowsAddresses(t) {
+			fmt.Printf("transaction invalid: %s: its address kind is not active\n", t.ID)
+			continue
+		}
+		if err := t.Validate(ctx.Time); err != nil {
+			fmt.Printf("%s\n", err)
+			continue
 		}
 		ok, err := t.VerifyTransaction()
 		if err != nil || !ok {
 			fmt.Printf("transaction invalid: %s\n", err)
 			continue
 		}
+		// applying to a working copy of the ledger stops several pending
+		// transactions from the same sender overspending together.
+		if err := state.ApplyTransaction(t, ctx); err != nil {
+			fmt.Printf("transaction invalid: %s\n", err)
+			continue
+		}
 
 		batch = append(batch, t)
 	}
@@ -190,12 +236,12 @@ func (c *Chain) ValidateTransactions(trans []tran.Transaction) []tran.Transactio
 }
 
 // CreateRewardTransaction returns a block reward transaction.
-func (c *Chain) CreateRewardTransaction(ts time.Time, symbol string, keyPair *ecdsa.PrivateKey) (tran.Transaction, error) {
+func (c *Chain) CreateRewardTransaction(ts time.Time, symbol string, amount uint64, keyPair *ecdsa.PrivateKey) (tran.Transaction, error) {
 	validatorAddress, err := keys.GetAddress(keyPair)
 	if err != nil {
 		return tran.Transaction{}, err
 	}
-	t, err := tran.NewTransaction(symbol, validatorAddress, validatorAddress, 1, "block reward", ts)
+	t, err := tran.NewCoinbaseTransaction(symbol, validatorAddress, amount, ts)
 	if err != nil {
 		return tran.Transaction{}, err
 	}
@@ -205,46 +251,122 @@ func (c *Chain) CreateRewardTransaction(ts time.Time, symbol string, keyPair *ec
 	return t, err
 }
 
-// GetBalanceForAddress computes the balance of an address.
-func (c *Chain) GetBalanceForAddress(a string) map[string]float64 {
-	balances := make(map[string]float64)
-
+// State replays the chain and returns the resulting ledger.
+func (c *Chain) State() *State {
+	state := NewState()
 	for _, b := range c.Blocks {
-		for _, t := range b.Transactions {
-			if t.Source == a || t.Destination == a {
-				if _, ok := balances[t.Symbol]; !ok {
-					balances[t.Symbol] = 0.0
-				}
-			}
-			if t.Source == a && t.Destination == a && t.Memo == "block reward" {
-				balances[t.Symbol] += t.Amount
-			} else if t.Source == a {
-				balances[t.Symbol] -= t.Amount
-			} else if t.Destination == a {
-				balances[t.Symbol] += t.Amount
-			}
+		if err := state.ApplyBlock(b); err != nil {
+			fmt.Printf("Error: %s\n", err)
 		}
 	}
 
-	return balances
+	return state
+}
+
+// GetTokens returns every token on the chain.
+func (c *Chain) GetTokens() []TokenInfo {
+	return c.State().Tokens()
+}
+
+// GetToken returns the token with the supplied symbol.
+func (c *Chain) GetToken(symbol string) (TokenInfo, bool) {
+	return c.State().Token(symbol)
+}
+
+// GetContract returns the hash time-locked contract with the supplied id.
+func (c *Chain) GetContract(id string) (Contract, bool) {
+	return c.State().Contract(id)
+}
+
+// ResolveName returns the record for a name that is registered at the chain's
+// next height.
+func (c *Chain) ResolveName(name string) (NameRecord, bool) {
+	return c.State().ResolveName(name, uint64(len(c.Blocks)))
+}
+
+// GetAnchor returns the earliest anchor of a hex encoded digest.
+func (c *Chain) GetAnchor(digest string) (AnchorRecord, bool) {
+	return c.State().Anchor(digest)
+}
+
+// GetNFT returns a unique token and its ownership history.
+func (c *Chain) GetNFT(id string) (NFT, []NFTTransfer, bool) {
+	state := c.State()
+	n, ok := state.NFT(id)
+	return n, state.NFTHistory(id), ok
+}
+
+// GetNFTsForAddress returns the unique tokens owned by an address.
+func (c *Chain) GetNFTsForAddress(a string) []NFT {
+	return c.State().NFTsOwnedBy(a)
+}
+
+// GetOrderBook returns the open orders of the base/quote market.
+func (c *Chain) GetOrderBook(base, quote string) OrderBook {
+	return c.State().OrderBook(base, quote)
+}
+
+// GetTrades returns the trades of the base/quote market.
+func (c *Chain) GetTrades(base, quote string) []Trade {
+	return c.State().Trades(base, quote)
 }
 
-// VerifyBalance confirms that a wallet contains a sufficient balance.
+// GetAllowances returns every allowance granted by an address.
+func (c *Chain) GetAllowances(owner string) []Allowance {
+	return c.State().Allowances(owner)
+}
+
+// GetChannel returns the payment channel with the supplied id.
+func (c *Chain) GetChannel(id string) (Channel, bool) {
+	return c.State().Channel(id)
+}
+
+// GetTransactionsForAddress returns every transaction sent or received by an
+// address, newest first.
+func (c *Chain) GetTransactionsForAddress(a string) []AddressTransaction {
+	return AddressHistory(c.Blocks, a)
+}
+
+// GetParams returns the consensus rules for the next block and the approved
+// changes still to come.
+func (c *Chain) GetParams() ParamsInfo {
+	return c.State().ParamsInfo(uint64(len(c.Blocks)))
+}
+
+// GetProposals returns every governance proposal, the most recent first.
+func (c *Chain) GetProposals() []Proposal {
+	return c.State().Proposals()
+}
+
+// GetProposal returns the governance proposal with the supplied id.
+func (c *Chain) GetProposal(id string) (Proposal, bool) {
+	return c.State().Proposal(id)
+}
+
+// GetBalanceForAddress computes the spendable and locked balances of an
+// address in base units.
+func (c *Chain) GetBalanceForAddress(a string) AddressBalances {
+	return c.State().AddressBalances(a)
+}
+
+// VerifyBalance confirms that a transaction can be applied to the ledger,
+// typically that its wallet contains a sufficient unlocked balance.
 func (c *Chain) VerifyBalance(t tran.Transaction) (bool, error) {
-	cBalance := c.GetBalanceForAddress(t.Source)
-	if _, ok := cBalance[t.Symbol]; !ok {
-		return false, fmt.Errorf("Insufficient funds to perform transaction")
-	}
-	if cBalance[t.Symbol] < t.Amount {
-		return false, fmt.Errorf("Insufficient funds to perform transaction")
+	ctx := Context{Height: uint64(len(c.Blocks)), Time: time.Now().UTC()}
+	state := c.State()
+	state.Unlock(ctx)
+	if err := state.CheckTransaction(t, ctx); err != nil {
+		return false, err
 	}
 
 	return true, nil
 }
 
-// FindIncrementor implements a simple proof of work algorithm.
+// FindIncrementor implements a simple proof of work algorithm, searching for
+// an incrementor meeting the difficulty of the next block.
 func (c *Chain) FindIncrementor(done chan interface{}) <-chan uint64 {
 	fmt.Println("Finding next incrementor")
+	difficulty := c.State().Params(uint64(len(c.Blocks))).Difficulty
 	incrementorStream := make(chan uint64)
 	go func() {
 		defer close(incrementorStream)
@@ -255,7 +377,7 @@ func (c *Chain) FindIncrementor(done chan interface{}) <-chan uint64 {
 			case <-done:
 				return
 			default:
-				if incrementor%INCREMENTOR_DIVISOR == 0 && incrementor != 0 {
+				if incrementor%difficulty == 0 && incrementor != 0 {
 					incrementorStream <- incrementor
 					return
 				}
@@ -302,6 +424,11 @@ func (c *Chain) FetchBlocks() []*block.Block {
 			continue
 		}
 
+		if err := ValidateBlocks(tmpBlocks, time.Now().UTC()); err != nil {
+			fmt.Printf("Rejected chain from %s: %s\n", peer, err)
+			continue
+		}
+
 		if len(tmpBlocks) > len(blocks) {
 			blocks = tmpBlocks
 		}
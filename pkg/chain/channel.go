@@ -0,0 +1,172 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// Channel states.
+const (
+	ChannelOpen    = "open"
+	ChannelClosing = "closing"
+	ChannelSettled = "settled"
+)
+
+func init() {
+	RegisterHandler(tran.TypeOpenChannel, openChannelHandler{})
+	RegisterHandler(tran.TypeCloseChannel, closeChannelHandler{})
+	RegisterHandler(tran.TypeSettleChannel, settleChannelHandler{})
+}
+
+// Channel is a unidirectional payment channel, identified by the ID of the
+// transaction that opened it. Paid is what the latest update submitted on
+// chain owes the recipient; the rest of the capacity returns to the sender.
+type Channel struct {
+	ID        string `json:"id"`
+	Sender    string `json:"sender"`
+	Recipient string `json:"recipient"`
+	Symbol    string `json:"symbol"`
+	Capacity  uint64 `json:"capacity"`
+	Window    uint64 `json:"window"`
+	State     string `json:"state"`
+	Paid      uint64 `json:"paid"`
+	ClosesAt  uint64 `json:"closes_at,omitempty"`
+}
+
+// Channel returns the payment channel with the supplied id.
+func (s *State) Channel(id string) (Channel, bool) {
+	c, ok := s.channels[id]
+	return c, ok
+}
+
+// settleChannel pays out a channel's balances and marks it settled.
+func (s *State) settleChannel(c Channel) {
+	c.State = ChannelSettled
+	s.channels[c.ID] = c
+	s.Credit(c.Recipient, c.Symbol, c.Paid)
+	s.Credit(c.Sender, c.Symbol, c.Capacity-c.Paid)
+}
+
+// openChannelHandler moves funds from the sender into a new channel. The
+// sender must be a single-key address so the chain can verify its updates.
+type openChannelHandler struct{}
+
+func (openChannelHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if _, err := keys.DecodeAddress(t.Source); err != nil {
+		return fmt.Errorf("Transaction invalid: %s: channels must be opened from a single-key address", t.ID)
+	}
+	if t.Destination == t.Source {
+		return fmt.Errorf("Transaction invalid: %s: a channel must pay another address", t.ID)
+	}
+	if s.Balance(t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	_, err := t.DecodePayload()
+	return err
+}
+
+func (openChannelHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+
+	s.Debit(t.Source, t.Symbol, t.Amount)
+	s.channels[t.ID] = Channel{
+		ID:        t.ID,
+		Sender:    t.Source,
+		Recipient: t.Destination,
+		Symbol:    t.Symbol,
+		Capacity:  t.Amount,
+		Window:    p.(*tran.OpenChannel).Window,
+		State:     ChannelOpen,
+	}
+}
+
+// closeChannelHandler submits a channel's latest update. The recipient has
+// no reason to submit anything but its best update, so its close settles the
+// channel. The sender could submit a stale one, so its close only starts the
+// dispute window, during which the recipient may close with a later update.
+type closeChannelHandler struct{}
+
+func (closeChannelHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	update := p.(*tran.CloseChannel).Update
+	c, ok := s.channels[update.Channel]
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: unknown channel %s", t.ID, update.Channel)
+	}
+	switch t.Source {
+	case c.Sender:
+		if c.State != ChannelOpen {
+			return fmt.Errorf("Transaction invalid: %s: channel %s already %s", t.ID, c.ID, c.State)
+		}
+		if ctx.Height+c.Window < ctx.Height {
+			return fmt.Errorf("Transaction invalid: %s: dispute window of channel %s ends past the last height", t.ID, c.ID)
+		}
+	case c.Recipient:
+		if c.State == ChannelSettled {
+			return fmt.Errorf("Transaction invalid: %s: channel %s already %s", t.ID, c.ID, c.State)
+		}
+		if update.Paid < c.Paid {
+			return fmt.Errorf("Transaction invalid: %s: update pays less than the one already submitted", t.ID)
+		}
+	default:
+		return fmt.Errorf("Transaction invalid: %s: only the parties may close channel %s", t.ID, c.ID)
+	}
+	if update.Paid > c.Capacity {
+		return fmt.Errorf("Transaction invalid: %s: update pays more than channel %s holds", t.ID, c.ID)
+	}
+	if !update.Verify(c.Sender) {
+		return fmt.Errorf("Transaction invalid: %s: update is not signed by the channel's sender", t.ID)
+	}
+	return nil
+}
+
+func (closeChannelHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	update := p.(*tran.CloseChannel).Update
+
+	c := s.channels[update.Channel]
+	c.Paid = update.Paid
+	if t.Source == c.Recipient {
+		s.settleChannel(c)
+		return
+	}
+	c.State = ChannelClosing
+	c.ClosesAt = ctx.Height + c.Window
+	s.channels[c.ID] = c
+}
+
+// settleChannelHandler pays out a channel closed by its sender once the
+// dispute window has passed.
+type settleChannelHandler struct{}
+
+func (settleChannelHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	id := p.(*tran.SettleChannel).Channel
+	c, ok := s.channels[id]
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: unknown channel %s", t.ID, id)
+	}
+	if t.Source != c.Sender && t.Source != c.Recipient {
+		return fmt.Errorf("Transaction invalid: %s: only the parties may settle channel %s", t.ID, c.ID)
+	}
+	if c.State != ChannelClosing {
+		return fmt.Errorf("Transaction invalid: %s: channel %s is %s", t.ID, c.ID, c.State)
+	}
+	if ctx.Height < c.ClosesAt {
+		return fmt.Errorf("Transaction invalid: %s: channel %s is in its dispute window until height %d", t.ID, c.ID, c.ClosesAt)
+	}
+	return nil
+}
+
+func (settleChannelHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	s.settleChannel(s.channels[p.(*tran.SettleChannel).Channel])
+}
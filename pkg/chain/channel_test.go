@@ -0,0 +1,93 @@
+package chain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChannelDispute verifies a sender closing with a stale update can be
+// answered by the recipient within the dispute window, and that an
+// unanswered close settles once the window has passed.
+func TestChannelDispute(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	key, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	sender, err := keys.GetAddress(key)
+	assert.Nil(t, err)
+
+	state := NewState()
+	state.Credit(sender, "RKY", 20)
+
+	open, err := tran.NewOpenChannelTransaction("RKY", sender, "bob", 10, 5, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(open, Context{Height: 1}))
+	assert.Equal(t, uint64(10), state.Balance(sender, "RKY"))
+
+	stale := tran.ChannelUpdate{Channel: open.ID, Paid: 2}
+	assert.Nil(t, stale.Sign(key))
+	latest := tran.ChannelUpdate{Channel: open.ID, Paid: 6}
+	assert.Nil(t, latest.Sign(key))
+
+	forged := tran.ChannelUpdate{Channel: open.ID, Paid: 10, R: latest.R, S: latest.S}
+	forgedClose, err := tran.NewCloseChannelTransaction("bob", forged, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(forgedClose, Context{Height: 2}))
+
+	senderClose, err := tran.NewCloseChannelTransaction(sender, stale, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(senderClose, Context{Height: 2}))
+	c, _ := state.Channel(open.ID)
+	assert.Equal(t, ChannelClosing, c.State)
+	assert.Equal(t, uint64(7), c.ClosesAt)
+
+	early, err := tran.NewSettleChannelTransaction(sender, open.ID, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(early, Context{Height: 6}))
+
+	answer, err := tran.NewCloseChannelTransaction("bob", latest, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(answer, Context{Height: 6}))
+	assert.Equal(t, uint64(6), state.Balance("bob", "RKY"))
+	assert.Equal(t, uint64(14), state.Balance(sender, "RKY"))
+	c, _ = state.Channel(open.ID)
+	assert.Equal(t, ChannelSettled, c.State)
+
+	second, err := tran.NewOpenChannelTransaction("RKY", sender, "bob", 4, 5, tm.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(second, Context{Height: 7}))
+	closeEmpty, err := tran.NewCloseChannelTransaction(sender, tran.ChannelUpdate{Channel: second.ID}, tm.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(closeEmpty, Context{Height: 7}))
+	settle, err := tran.NewSettleChannelTransaction("bob", second.ID, tm.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(settle, Context{Height: 12}))
+	assert.Equal(t, uint64(14), state.Balance(sender, "RKY"))
+}
+
+// TestChannelCloseOverflow verifies a sender can't start a dispute window
+// that would end past the last height.
+func TestChannelCloseOverflow(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	key, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	sender, err := keys.GetAddress(key)
+	assert.Nil(t, err)
+
+	state := NewState()
+	state.Credit(sender, "RKY", 10)
+
+	open, err := tran.NewOpenChannelTransaction("RKY", sender, "bob", 10, 5, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(open, Context{Height: 1}))
+
+	closeLate, err := tran.NewCloseChannelTransaction(sender, tran.ChannelUpdate{Channel: open.ID}, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(closeLate, Context{Height: math.MaxUint64 - 1}))
+	assert.Nil(t, state.CheckTransaction(closeLate, Context{Height: math.MaxUint64 - 5}))
+}
@@ -0,0 +1,250 @@
+package chain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// Proposal states.
+const (
+	ProposalVoting   = "voting"
+	ProposalApproved = "approved"
+	ProposalRejected = "rejected"
+)
+
+const (
+	// GovernanceSymbol is the token whose stake carries voting weight.
+	GovernanceSymbol = "RKY"
+
+	// VotingPeriod is how many blocks a proposal is open for votes.
+	VotingPeriod = 1000
+
+	// ActivationDelay is how many blocks after its vote closes an approved
+	// change takes effect, giving validators time to see it coming.
+	ActivationDelay = 1000
+
+	// QuorumDivisor sets the quorum: the stake voting on a proposal must be
+	// at least the total stake divided by QuorumDivisor.
+	QuorumDivisor = 3
+
+	// MaxDifficultyChange is the most a single proposal may multiply or
+	// divide the difficulty by, so an approved change can't make blocks
+	// impossible to produce.
+	MaxDifficultyChange = 2
+)
+
+func init() {
+	RegisterHandler(tran.TypeProposeParam, proposeParamHandler{})
+	RegisterHandler(tran.TypeVote, voteHandler{})
+}
+
+// Proposal is a proposed change to a chain parameter, identified by the ID
+// of the transaction that made it. Votes map each voter to its choice.
+// Votes are weighted by the voter's stake when the vote closes, so stake
+// moved after voting doesn't count twice.
+type Proposal struct {
+	ID         string            `json:"id"`
+	Proposer   string            `json:"proposer"`
+	Param      string            `json:"param"`
+	Value      uint64            `json:"value"`
+	VotingEnds uint64            `json:"voting_ends"`
+	Activates  uint64            `json:"activates"`
+	State      string            `json:"state"`
+	Votes      map[string]string `json:"votes"`
+	Yes        uint64            `json:"yes"`
+	No         uint64            `json:"no"`
+}
+
+// ParamsInfo describes the consensus rules for a height, along with approved
+// changes that have not taken effect yet.
+type ParamsInfo struct {
+	Height    uint64     `json:"height"`
+	Params    Params     `json:"params"`
+	Scheduled []Proposal `json:"scheduled"`
+}
+
+// With returns the rules with a governed parameter set to value.
+func (p Params) With(param string, value uint64) Params {
+	switch param {
+	case tran.ParamReward:
+		p.Reward = value
+	case tran.ParamDifficulty:
+		p.Difficulty = value
+	case tran.ParamBlockSize:
+		p.MaxBlockSize = value
+	}
+	return p
+}
+
+// Params returns the consensus rules for the block at height: those of the
+// fork in force, with every approved change activated by then applied in
+// the order it was approved. A difficulty change is bounded by the
+// difficulty it replaces, since several proposals checked against the same
+// difficulty may activate one after another.
+func (s *State) Params(height uint64) Params {
+	params := ParamsAt(height)
+	for _, p := range s.approved {
+		if p.Activates > height {
+			continue
+		}
+		value := p.Value
+		if p.Param == tran.ParamDifficulty {
+			value = boundDifficulty(params.Difficulty, value)
+		}
+		params = params.With(p.Param, value)
+	}
+	return params
+}
+
+// boundDifficulty limits a proposed difficulty to within MaxDifficultyChange
+// of current.
+func boundDifficulty(current, value uint64) uint64 {
+	if min := current / MaxDifficultyChange; value < min {
+		return min
+	}
+	if max := current * MaxDifficultyChange; value > max {
+		return max
+	}
+	return value
+}
+
+// ParamsInfo returns the rules for the block at height and the approved
+// changes still to come.
+func (s *State) ParamsInfo(height uint64) ParamsInfo {
+	info := ParamsInfo{Height: height, Params: s.Params(height), Scheduled: []Proposal{}}
+	for _, p := range s.approved {
+		if p.Activates > height {
+			info.Scheduled = append(info.Scheduled, p)
+		}
+	}
+	return info
+}
+
+// Proposal returns the proposal with the supplied id.
+func (s *State) Proposal(id string) (Proposal, bool) {
+	p, ok := s.proposals[id]
+	return p, ok
+}
+
+// Proposals returns every proposal, the most recent first.
+func (s *State) Proposals() []Proposal {
+	proposals := []Proposal{}
+	for _, p := range s.proposals {
+		proposals = append(proposals, p)
+	}
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].VotingEnds != proposals[j].VotingEnds {
+			return proposals[i].VotingEnds > proposals[j].VotingEnds
+		}
+		return proposals[i].ID < proposals[j].ID
+	})
+	return proposals
+}
+
+// Tally closes every proposal whose vote ends at or before the block
+// described by ctx. A proposal is approved if the stake voting on it meets
+// the quorum and more of it voted yes than no. Proposals are closed in ID
+// order so every validator approves them in the same order.
+func (s *State) Tally(ctx Context) {
+	ids := []string{}
+	for id, p := range s.proposals {
+		if p.State == ProposalVoting && p.VotingEnds <= ctx.Height {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	sort.Strings(ids)
+
+	var total uint64
+	for _, stakes := range s.stakes {
+		total += stakes[GovernanceSymbol]
+	}
+	for _, id := range ids {
+		p := s.proposals[id]
+		for voter, choice := range p.Votes {
+			if choice == tran.VoteYes {
+				p.Yes += s.Stake(voter, GovernanceSymbol)
+			} else {
+				p.No += s.Stake(voter, GovernanceSymbol)
+			}
+		}
+		p.State = ProposalRejected
+		if p.Yes+p.No > 0 && p.Yes+p.No >= total/QuorumDivisor && p.Yes > p.No {
+			p.State = ProposalApproved
+			s.approved = append(s.approved, p)
+		}
+		s.proposals[id] = p
+	}
+}
+
+// proposeParamHandler opens a proposal for votes. Only stakers may propose.
+type proposeParamHandler struct{}
+
+func (proposeParamHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if s.Stake(t.Source, GovernanceSymbol) == 0 {
+		return fmt.Errorf("Transaction invalid: %s: only addresses staking %s may propose", t.ID, GovernanceSymbol)
+	}
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	propose := p.(*tran.ProposeParam)
+	if propose.Param == tran.ParamDifficulty {
+		current := s.Params(ctx.Height).Difficulty
+		if boundDifficulty(current, propose.Value) != propose.Value {
+			return fmt.Errorf("Transaction invalid: %s: difficulty may change by at most %dx from %d", t.ID, MaxDifficultyChange, current)
+		}
+	}
+	return nil
+}
+
+func (proposeParamHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	propose := p.(*tran.ProposeParam)
+
+	s.proposals[t.ID] = Proposal{
+		ID:         t.ID,
+		Proposer:   t.Source,
+		Param:      propose.Param,
+		Value:      propose.Value,
+		VotingEnds: ctx.Height + VotingPeriod,
+		Activates:  ctx.Height + VotingPeriod + ActivationDelay,
+		State:      ProposalVoting,
+		Votes:      map[string]string{},
+	}
+}
+
+// voteHandler records a staker's vote on an open proposal, replacing any
+// earlier vote by the same address.
+type voteHandler struct{}
+
+func (voteHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	vote := p.(*tran.Vote)
+	proposal, ok := s.proposals[vote.Proposal]
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: unknown proposal %s", t.ID, vote.Proposal)
+	}
+	if proposal.State != ProposalVoting || ctx.Height >= proposal.VotingEnds {
+		return fmt.Errorf("Transaction invalid: %s: voting on proposal %s has closed", t.ID, proposal.ID)
+	}
+	if s.Stake(t.Source, GovernanceSymbol) == 0 {
+		return fmt.Errorf("Transaction invalid: %s: only addresses staking %s may vote", t.ID, GovernanceSymbol)
+	}
+	return nil
+}
+
+func (voteHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	vote := p.(*tran.Vote)
+
+	proposal := s.proposals[vote.Proposal]
+	proposal.Votes[t.Source] = vote.Choice
+}
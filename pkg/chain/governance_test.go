@@ -0,0 +1,106 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGovernance verifies a proposal approved by stake-weighted vote takes
+// effect at its activation height, and that a proposal without a majority
+// is rejected.
+func TestGovernance(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	state := NewState()
+	for address, amount := range map[string]uint64{"alice": 6, "bob": 3, "carol": 1} {
+		state.Credit(address, GovernanceSymbol, amount)
+		stake, err := tran.NewStakeTransaction(GovernanceSymbol, address, amount, tm)
+		assert.Nil(t, err)
+		assert.Nil(t, state.ApplyTransaction(stake, Context{}))
+	}
+
+	outsider, err := tran.NewProposeParamTransaction("dave", tran.ParamReward, 5, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(outsider, Context{Height: 1}))
+
+	reward, err := tran.NewProposeParamTransaction("carol", tran.ParamReward, 5, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(reward, Context{Height: 1}))
+	size, err := tran.NewProposeParamTransaction("bob", tran.ParamBlockSize, 2<<20, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(size, Context{Height: 1}))
+
+	votes := []struct {
+		voter, proposal, choice string
+	}{
+		{"alice", reward.ID, tran.VoteYes},
+		{"bob", reward.ID, tran.VoteNo},
+		{"alice", size.ID, tran.VoteNo},
+		{"bob", size.ID, tran.VoteYes},
+		{"carol", size.ID, tran.VoteYes},
+	}
+	for _, v := range votes {
+		vote, err := tran.NewVoteTransaction(v.voter, v.proposal, v.choice, tm)
+		assert.Nil(t, err)
+		assert.Nil(t, state.ApplyTransaction(vote, Context{Height: 2}))
+	}
+
+	ends := uint64(1 + VotingPeriod)
+	late, err := tran.NewVoteTransaction("carol", reward.ID, tran.VoteYes, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(late, Context{Height: ends}))
+
+	state.Tally(Context{Height: ends})
+	approved, _ := state.Proposal(reward.ID)
+	assert.Equal(t, ProposalApproved, approved.State)
+	assert.Equal(t, uint64(6), approved.Yes)
+	assert.Equal(t, uint64(3), approved.No)
+	rejected, _ := state.Proposal(size.ID)
+	assert.Equal(t, ProposalRejected, rejected.State)
+
+	activates := ends + ActivationDelay
+	assert.Equal(t, uint64(1), state.Params(activates-1).Reward)
+	assert.Equal(t, []Proposal{approved}, state.ParamsInfo(activates-1).Scheduled)
+	assert.Equal(t, uint64(5), state.Params(activates).Reward)
+	assert.Equal(t, ParamsAt(activates).MaxBlockSize, state.Params(activates).MaxBlockSize)
+	assert.Empty(t, state.ParamsInfo(activates).Scheduled)
+}
+
+// TestDifficultyProposal verifies a proposal may move the difficulty by at
+// most MaxDifficultyChange, and that changes checked against the same
+// difficulty are bounded again by the one they replace when they activate.
+func TestDifficultyProposal(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	state := NewState()
+	state.Credit("alice", GovernanceSymbol, 1)
+	stake, err := tran.NewStakeTransaction(GovernanceSymbol, "alice", 1, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(stake, Context{}))
+
+	current := state.Params(1).Difficulty
+	tooHigh, err := tran.NewProposeParamTransaction("alice", tran.ParamDifficulty, current*MaxDifficultyChange+1, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(tooHigh, Context{Height: 1}))
+	tooLow, err := tran.NewProposeParamTransaction("alice", tran.ParamDifficulty, current/MaxDifficultyChange-1, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(tooLow, Context{Height: 1}))
+
+	up, err := tran.NewProposeParamTransaction("alice", tran.ParamDifficulty, current*MaxDifficultyChange, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(up, Context{Height: 1}))
+	down, err := tran.NewProposeParamTransaction("alice", tran.ParamDifficulty, current/MaxDifficultyChange, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(down, Context{Height: 1}))
+	for _, p := range []tran.Transaction{up, down} {
+		vote, err := tran.NewVoteTransaction("alice", p.ID, tran.VoteYes, tm)
+		assert.Nil(t, err)
+		assert.Nil(t, state.ApplyTransaction(vote, Context{Height: 2}))
+	}
+
+	ends := uint64(1 + VotingPeriod)
+	state.Tally(Context{Height: ends})
+	assert.Equal(t, current, state.Params(ends+ActivationDelay).Difficulty)
+}
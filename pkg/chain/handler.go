@@ -0,0 +1,74 @@
+package chain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// Context describes the block a transaction is checked or applied in.
+type Context struct {
+	Height    uint64
+	Time      time.Time
+	Validator string
+}
+
+// Handler checks and applies transactions of one type against the ledger.
+type Handler interface {
+	// Check reports whether t can be applied to s. It must not modify s.
+	Check(s *State, t tran.Transaction, ctx Context) error
+
+	// Apply applies t to s. It is only called after Check succeeds.
+	Apply(s *State, t tran.Transaction, ctx Context)
+}
+
+var handlers = map[string]Handler{}
+
+// RegisterHandler registers the handler for a transaction type. It is meant
+// to be called from init functions, and panics if the type already has a
+// handler.
+func RegisterHandler(txType string, h Handler) {
+	if _, ok := handlers[txType]; ok {
+		panic(fmt.Sprintf("chain: handler for %s registered twice", txType))
+	}
+	handlers[txType] = h
+}
+
+func init() {
+	RegisterHandler(tran.TypeTransfer, transferHandler{})
+	RegisterHandler(tran.TypeCoinbase, coinbaseHandler{})
+	RegisterHandler(tran.TypeStealthTransfer, transferHandler{})
+}
+
+// transferHandler moves funds from the source to the destination. Stealth
+// transfers are handled the same way; their payload only matters to the
+// recipient's wallet.
+type transferHandler struct{}
+
+func (transferHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if s.Balance(t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	return nil
+}
+
+func (transferHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	s.Debit(t.Source, t.Symbol, t.Amount)
+	s.Credit(t.Destination, t.Symbol, t.Amount)
+}
+
+// coinbaseHandler credits a block reward to the validator. The reward
+// schedule is enforced per block by ValidateRewards.
+type coinbaseHandler struct{}
+
+func (coinbaseHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if t.Destination != ctx.Validator {
+		return fmt.Errorf("Transaction invalid: %s: coinbase is not paid to the block validator", t.ID)
+	}
+	return nil
+}
+
+func (coinbaseHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	s.Mint(t.Destination, t.Symbol, t.Amount)
+}
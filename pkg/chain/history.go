@@ -0,0 +1,29 @@
+package chain
+
+import (
+	"github.com/datravis/lolachain/pkg/block"
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// AddressTransaction is a transaction sent or received by an address,
+// together with the height of the block that included it.
+type AddressTransaction struct {
+	Height      uint64           `json:"height"`
+	Transaction tran.Transaction `json:"transaction"`
+}
+
+// AddressHistory returns every transaction in blocks sent or received by
+// address, newest first.
+func AddressHistory(blocks []*block.Block, address string) []AddressTransaction {
+	history := []AddressTransaction{}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		for j := len(b.Transactions) - 1; j >= 0; j-- {
+			t := b.Transactions[j]
+			if t.Source == address || t.Destination == address {
+				history = append(history, AddressTransaction{Height: b.Index, Transaction: t})
+			}
+		}
+	}
+	return history
+}
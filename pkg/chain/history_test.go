@@ -0,0 +1,34 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/block"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAddressHistory verifies an address's history lists the transactions it
+// sent or received, newest first.
+func TestAddressHistory(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	first, err := tran.NewTransaction("RKY", "alice", "bob", 1, "", tm)
+	assert.Nil(t, err)
+	unrelated, err := tran.NewTransaction("RKY", "carol", "dave", 1, "", tm)
+	assert.Nil(t, err)
+	second, err := tran.NewTransaction("RKY", "bob", "alice", 1, "", tm)
+	assert.Nil(t, err)
+
+	blocks := []*block.Block{
+		{Index: 0, Transactions: []tran.Transaction{first, unrelated}},
+		{Index: 1, Transactions: []tran.Transaction{second}},
+	}
+
+	assert.Equal(t, []AddressTransaction{
+		{Height: 1, Transaction: second},
+		{Height: 0, Transaction: first},
+	}, AddressHistory(blocks, "alice"))
+	assert.Equal(t, []AddressTransaction{}, AddressHistory(blocks, "erin"))
+}
@@ -0,0 +1,156 @@
+package chain
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// Contract states.
+const (
+	ContractOpen     = "open"
+	ContractRedeemed = "redeemed"
+	ContractRefunded = "refunded"
+)
+
+func init() {
+	RegisterHandler(tran.TypeHTLC, htlcHandler{})
+	RegisterHandler(tran.TypeHTLCRedeem, htlcRedeemHandler{})
+	RegisterHandler(tran.TypeHTLCRefund, htlcRefundHandler{})
+}
+
+// Contract is a hash time-locked contract, identified by the ID of the
+// transaction that created it. Once redeemed it keeps the revealed preimage,
+// which the other side of a swap needs to redeem its own contract.
+type Contract struct {
+	ID        string `json:"id"`
+	Sender    string `json:"sender"`
+	Recipient string `json:"recipient"`
+	Symbol    string `json:"symbol"`
+	Amount    uint64 `json:"amount"`
+	Hash      []byte `json:"hash"`
+	Timeout   uint64 `json:"timeout"`
+	State     string `json:"state"`
+	Preimage  []byte `json:"preimage,omitempty"`
+}
+
+// Contract returns the contract with the supplied id.
+func (s *State) Contract(id string) (Contract, bool) {
+	c, ok := s.contracts[id]
+	return c, ok
+}
+
+// openContract returns the contract a redeem or refund refers to, if it is
+// still open.
+func (s *State) openContract(t tran.Transaction, id string) (Contract, error) {
+	c, ok := s.contracts[id]
+	if !ok {
+		return c, fmt.Errorf("Transaction invalid: %s: unknown contract %s", t.ID, id)
+	}
+	if c.State != ContractOpen {
+		return c, fmt.Errorf("Transaction invalid: %s: contract %s already %s", t.ID, id, c.State)
+	}
+	return c, nil
+}
+
+// htlcHandler moves funds from the source into a new contract.
+type htlcHandler struct{}
+
+func (htlcHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if s.Balance(t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	if p.(*tran.HTLC).Timeout <= ctx.Height {
+		return fmt.Errorf("Transaction invalid: %s: contract has already timed out", t.ID)
+	}
+	return nil
+}
+
+func (htlcHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	htlc := p.(*tran.HTLC)
+
+	s.Debit(t.Source, t.Symbol, t.Amount)
+	s.contracts[t.ID] = Contract{
+		ID:        t.ID,
+		Sender:    t.Source,
+		Recipient: t.Destination,
+		Symbol:    t.Symbol,
+		Amount:    t.Amount,
+		Hash:      htlc.Hash,
+		Timeout:   htlc.Timeout,
+		State:     ContractOpen,
+	}
+}
+
+// htlcRedeemHandler pays a contract to its recipient, who must reveal the
+// preimage before the contract times out.
+type htlcRedeemHandler struct{}
+
+func (htlcRedeemHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	redeem := p.(*tran.HTLCRedeem)
+	c, err := s.openContract(t, redeem.Contract)
+	if err != nil {
+		return err
+	}
+	if t.Source != c.Recipient {
+		return fmt.Errorf("Transaction invalid: %s: only the recipient may redeem contract %s", t.ID, c.ID)
+	}
+	if ctx.Height >= c.Timeout {
+		return fmt.Errorf("Transaction invalid: %s: contract %s has timed out", t.ID, c.ID)
+	}
+	if !bytes.Equal(tran.HashSecret(redeem.Preimage), c.Hash) {
+		return fmt.Errorf("Transaction invalid: %s: preimage does not match contract %s", t.ID, c.ID)
+	}
+	return nil
+}
+
+func (htlcRedeemHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	redeem := p.(*tran.HTLCRedeem)
+
+	c := s.contracts[redeem.Contract]
+	c.State = ContractRedeemed
+	c.Preimage = redeem.Preimage
+	s.contracts[c.ID] = c
+	s.Credit(c.Recipient, c.Symbol, c.Amount)
+}
+
+// htlcRefundHandler returns a timed out contract to its sender.
+type htlcRefundHandler struct{}
+
+func (htlcRefundHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	c, err := s.openContract(t, p.(*tran.HTLCRefund).Contract)
+	if err != nil {
+		return err
+	}
+	if t.Source != c.Sender {
+		return fmt.Errorf("Transaction invalid: %s: only the sender may refund contract %s", t.ID, c.ID)
+	}
+	if ctx.Height < c.Timeout {
+		return fmt.Errorf("Transaction invalid: %s: contract %s has not timed out", t.ID, c.ID)
+	}
+	return nil
+}
+
+func (htlcRefundHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+
+	c := s.contracts[p.(*tran.HTLCRefund).Contract]
+	c.State = ContractRefunded
+	s.contracts[c.ID] = c
+	s.Credit(c.Sender, c.Symbol, c.Amount)
+}
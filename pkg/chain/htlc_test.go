@@ -0,0 +1,83 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAtomicSwap verifies a swap of RKY for LOLA: redeeming one contract
+// reveals the secret that redeems the other.
+func TestAtomicSwap(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	secret := []byte("correct horse battery staple")
+	hash := tran.HashSecret(secret)
+
+	state := NewState()
+	state.Credit("alice", "RKY", 10)
+	state.Credit("bob", "LOLA", 20)
+
+	aliceLock, err := tran.NewHTLCTransaction("RKY", "alice", "bob", 10, tran.HTLC{Hash: hash, Timeout: 20}, tm)
+	assert.Nil(t, err)
+	bobLock, err := tran.NewHTLCTransaction("LOLA", "bob", "alice", 20, tran.HTLC{Hash: hash, Timeout: 10}, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(aliceLock, Context{Height: 1}))
+	assert.Nil(t, state.ApplyTransaction(bobLock, Context{Height: 2}))
+	assert.Equal(t, uint64(0), state.Balance("alice", "RKY"))
+	assert.Equal(t, uint64(0), state.Balance("bob", "LOLA"))
+
+	wrong, err := tran.NewHTLCRedeemTransaction("alice", bobLock.ID, []byte("guess"), tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(wrong, Context{Height: 3}))
+
+	aliceRedeem, err := tran.NewHTLCRedeemTransaction("alice", bobLock.ID, secret, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(aliceRedeem, Context{Height: 3}))
+	assert.Equal(t, uint64(20), state.Balance("alice", "LOLA"))
+
+	revealed, ok := state.Contract(bobLock.ID)
+	assert.True(t, ok)
+	assert.Equal(t, ContractRedeemed, revealed.State)
+
+	bobRedeem, err := tran.NewHTLCRedeemTransaction("bob", aliceLock.ID, revealed.Preimage, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(bobRedeem, Context{Height: 4}))
+	assert.Equal(t, uint64(10), state.Balance("bob", "RKY"))
+
+	refund, err := tran.NewHTLCRefundTransaction("bob", bobLock.ID, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(refund, Context{Height: 30}))
+}
+
+// TestHTLCRefund verifies a contract can only be refunded to its sender once
+// it has timed out, after which it can't be redeemed.
+func TestHTLCRefund(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	secret := []byte("secret")
+
+	state := NewState()
+	state.Credit("alice", "RKY", 10)
+
+	lock, err := tran.NewHTLCTransaction("RKY", "alice", "bob", 10, tran.HTLC{Hash: tran.HashSecret(secret), Timeout: 5}, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(lock, Context{Height: 1}))
+
+	refund, err := tran.NewHTLCRefundTransaction("alice", lock.ID, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(refund, Context{Height: 4}))
+
+	stranger, err := tran.NewHTLCRefundTransaction("bob", lock.ID, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(stranger, Context{Height: 5}))
+
+	redeem, err := tran.NewHTLCRedeemTransaction("bob", lock.ID, secret, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(redeem, Context{Height: 5}))
+
+	assert.Nil(t, state.ApplyTransaction(refund, Context{Height: 5}))
+	assert.Equal(t, uint64(10), state.Balance("alice", "RKY"))
+	assert.NotNil(t, state.CheckTransaction(redeem, Context{Height: 5}))
+}
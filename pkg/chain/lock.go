@@ -0,0 +1,98 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+func init() {
+	RegisterHandler(tran.TypeLockedTransfer, lockedTransferHandler{})
+}
+
+// LockedFunds are funds received by a locked transfer that can't be spent
+// until Lock expires.
+type LockedFunds struct {
+	ID     string    `json:"id"`
+	Symbol string    `json:"symbol"`
+	Amount uint64    `json:"amount"`
+	Lock   tran.Lock `json:"lock"`
+}
+
+// AddressBalances are the spendable and locked balances of an address, in
+// base units.
+type AddressBalances struct {
+	Unlocked map[string]uint64 `json:"unlocked"`
+	Locked   map[string]uint64 `json:"locked"`
+	Locks    []LockedFunds     `json:"locks"`
+}
+
+// Locked returns a copy of the locked funds held by address.
+func (s *State) Locked(address string) []LockedFunds {
+	return append([]LockedFunds{}, s.locks[address]...)
+}
+
+// AddressBalances returns the spendable and locked balances of address.
+func (s *State) AddressBalances(address string) AddressBalances {
+	b := AddressBalances{
+		Unlocked: s.Balances(address),
+		Locked:   make(map[string]uint64),
+		Locks:    s.Locked(address),
+	}
+	for _, l := range b.Locks {
+		b.Locked[l.Symbol] += l.Amount
+	}
+	return b
+}
+
+// Unlock credits every lock that has expired by the block described by ctx
+// to its holder's spendable balance. It is called before the transactions of
+// a block are applied.
+func (s *State) Unlock(ctx Context) {
+	for address, locks := range s.locks {
+		remaining := locks[:0]
+		for _, l := range locks {
+			if l.Lock.Unlocked(ctx.Height, ctx.Time) {
+				s.Credit(address, l.Symbol, l.Amount)
+			} else {
+				remaining = append(remaining, l)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(s.locks, address)
+		} else {
+			s.locks[address] = remaining
+		}
+	}
+}
+
+// lockedTransferHandler moves funds from the source into a lock held by the
+// destination.
+type lockedTransferHandler struct{}
+
+func (lockedTransferHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if s.Balance(t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	if _, err := t.DecodePayload(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (lockedTransferHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	lock := p.(*tran.Lock)
+
+	s.Debit(t.Source, t.Symbol, t.Amount)
+	if lock.Unlocked(ctx.Height, ctx.Time) {
+		s.Credit(t.Destination, t.Symbol, t.Amount)
+		return
+	}
+	s.locks[t.Destination] = append(s.locks[t.Destination], LockedFunds{
+		ID:     t.ID,
+		Symbol: t.Symbol,
+		Amount: t.Amount,
+		Lock:   *lock,
+	})
+}
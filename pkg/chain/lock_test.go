@@ -0,0 +1,58 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/block"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLockedTransfer verifies locked funds can't be spent until the block in
+// which their lock expires.
+func TestLockedTransfer(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	reward, err := tran.NewCoinbaseTransaction("RKY", "alice", 10, tm)
+	assert.Nil(t, err)
+	locked, err := tran.NewLockedTransferTransaction("RKY", "alice", "bob", 6, tran.Lock{Height: 3}, "vesting", tm)
+	assert.Nil(t, err)
+	spend, err := tran.NewTransaction("RKY", "bob", "carol", 4, "memo", tm)
+	assert.Nil(t, err)
+
+	state := NewState()
+	assert.Nil(t, state.ApplyBlock(&block.Block{Index: 1, Validator: "alice", Transactions: []tran.Transaction{reward, locked}}))
+	assert.Equal(t, uint64(4), state.Balance("alice", "RKY"))
+	assert.Equal(t, uint64(0), state.Balance("bob", "RKY"))
+
+	balances := state.AddressBalances("bob")
+	assert.Equal(t, uint64(6), balances.Locked["RKY"])
+	assert.Equal(t, 1, len(balances.Locks))
+	assert.Equal(t, locked.ID, balances.Locks[0].ID)
+
+	assert.NotNil(t, state.ApplyBlock(&block.Block{Index: 2, Validator: "alice", Transactions: []tran.Transaction{spend}}))
+
+	state.Unlock(Context{Height: 2})
+	assert.Equal(t, uint64(0), state.Balance("bob", "RKY"))
+
+	assert.Nil(t, state.ApplyBlock(&block.Block{Index: 3, Validator: "alice", Transactions: []tran.Transaction{spend}}))
+	assert.Equal(t, uint64(2), state.Balance("bob", "RKY"))
+	assert.Equal(t, uint64(4), state.Balance("carol", "RKY"))
+	assert.Equal(t, 0, len(state.Locked("bob")))
+}
+
+// TestLockedTransferExpired verifies a lock that has already expired credits
+// the destination directly.
+func TestLockedTransferExpired(t *testing.T) {
+	tm := time.Unix(100, 0).UTC()
+	reward, err := tran.NewCoinbaseTransaction("RKY", "alice", 10, tm)
+	assert.Nil(t, err)
+	locked, err := tran.NewLockedTransferTransaction("RKY", "alice", "bob", 6, tran.Lock{Time: tm}, "", tm)
+	assert.Nil(t, err)
+
+	state := NewState()
+	assert.Nil(t, state.ApplyBlock(&block.Block{Index: 1, Time: tm, Validator: "alice", Transactions: []tran.Transaction{reward, locked}}))
+	assert.Equal(t, uint64(6), state.Balance("bob", "RKY"))
+	assert.Equal(t, 0, len(state.Locked("bob")))
+}
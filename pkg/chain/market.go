@@ -0,0 +1,269 @@
+package chain
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+func init() {
+	RegisterHandler(tran.TypePlaceOrder, placeOrderHandler{})
+	RegisterHandler(tran.TypeCancelOrder, cancelOrderHandler{})
+}
+
+// Order is an open limit order. A sell order holds its Remaining base
+// units; a buy order holds Locked quote units, enough to pay for Remaining at
+// its limit price.
+type Order struct {
+	ID        string `json:"id"`
+	Owner     string `json:"owner"`
+	Base      string `json:"base"`
+	Quote     string `json:"quote"`
+	Side      string `json:"side"`
+	Price     uint64 `json:"price"`
+	Quantity  uint64 `json:"quantity"`
+	Remaining uint64 `json:"remaining"`
+	Locked    uint64 `json:"locked"`
+	// Seq orders placement, giving time priority between equal prices.
+	Seq uint64 `json:"-"`
+}
+
+// Trade is a match between a buy and a sell order, at the price of the order
+// that was already on the book.
+type Trade struct {
+	Price     uint64    `json:"price"`
+	Quantity  uint64    `json:"quantity"`
+	Buyer     string    `json:"buyer"`
+	Seller    string    `json:"seller"`
+	BuyOrder  string    `json:"buy_order"`
+	SellOrder string    `json:"sell_order"`
+	Height    uint64    `json:"height"`
+	Time      time.Time `json:"time"`
+}
+
+// OrderBook is the open orders of a market, best price first.
+type OrderBook struct {
+	Bids []Order `json:"bids"`
+	Asks []Order `json:"asks"`
+}
+
+// Market returns the key of the base/quote market.
+func Market(base, quote string) string {
+	return base + "-" + quote
+}
+
+// OrderBook returns the open orders of the base/quote market. Bids are sorted
+// by descending and asks by ascending price, each then by placement.
+func (s *State) OrderBook(base, quote string) OrderBook {
+	book := OrderBook{Bids: []Order{}, Asks: []Order{}}
+	for _, o := range s.orders {
+		if o.Base != base || o.Quote != quote {
+			continue
+		}
+		if o.Side == tran.SideBuy {
+			book.Bids = append(book.Bids, o)
+		} else {
+			book.Asks = append(book.Asks, o)
+		}
+	}
+	sort.Slice(book.Bids, func(i, j int) bool { return better(book.Bids[i], book.Bids[j]) })
+	sort.Slice(book.Asks, func(i, j int) bool { return better(book.Asks[i], book.Asks[j]) })
+	return book
+}
+
+// Trades returns the trades of the base/quote market, oldest first.
+func (s *State) Trades(base, quote string) []Trade {
+	return append([]Trade{}, s.trades[Market(base, quote)]...)
+}
+
+// better reports whether a has priority over b on the same side of a book.
+func better(a, b Order) bool {
+	if a.Price != b.Price {
+		if a.Side == tran.SideBuy {
+			return a.Price > b.Price
+		}
+		return a.Price < b.Price
+	}
+	return a.Seq < b.Seq
+}
+
+// cost returns the quote units paid for quantity base units at price, rounded
+// down, and false if it overflows.
+func (s *State) cost(base string, quantity, price uint64) (uint64, bool) {
+	unit := s.tokens[base].Units(1)
+	hi, lo := bits.Mul64(quantity, price)
+	if hi >= unit {
+		return 0, false
+	}
+	q, _ := bits.Div64(hi, lo, unit)
+	return q, true
+}
+
+// placeOrderHandler holds an order's funds and matches it against the book.
+type placeOrderHandler struct{}
+
+func (placeOrderHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	order := p.(*tran.PlaceOrder)
+	for _, symbol := range []string{order.Base, order.Quote} {
+		if _, ok := s.tokens[symbol]; !ok {
+			return fmt.Errorf("Transaction invalid: %s: unknown symbol %s", t.ID, symbol)
+		}
+	}
+
+	if order.Side == tran.SideSell {
+		if s.Balance(t.Source, order.Base) < order.Quantity {
+			return fmt.Errorf("Insufficient funds to perform transaction")
+		}
+		return nil
+	}
+	cost, ok := s.cost(order.Base, order.Quantity, order.Price)
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: order value overflows", t.ID)
+	}
+	if cost == 0 {
+		return fmt.Errorf("Transaction invalid: %s: order value rounds to zero", t.ID)
+	}
+	if s.Balance(t.Source, order.Quote) < cost {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	return nil
+}
+
+func (placeOrderHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	placed := p.(*tran.PlaceOrder)
+
+	s.orderSeq++
+	o := Order{
+		ID:        t.ID,
+		Owner:     t.Source,
+		Base:      placed.Base,
+		Quote:     placed.Quote,
+		Side:      placed.Side,
+		Price:     placed.Price,
+		Quantity:  placed.Quantity,
+		Remaining: placed.Quantity,
+		Seq:       s.orderSeq,
+	}
+	if o.Side == tran.SideSell {
+		s.Debit(o.Owner, o.Base, o.Quantity)
+	} else {
+		o.Locked, _ = s.cost(o.Base, o.Quantity, o.Price)
+		s.Debit(o.Owner, o.Quote, o.Locked)
+	}
+
+	s.match(&o, ctx)
+	if o.Remaining > 0 {
+		s.orders[o.ID] = o
+	}
+}
+
+// match fills the incoming order against the opposite side of its book while
+// the prices cross. Each trade is at the resting order's price.
+func (s *State) match(taker *Order, ctx Context) {
+	book := s.OrderBook(taker.Base, taker.Quote)
+	resting := book.Asks
+	if taker.Side == tran.SideSell {
+		resting = book.Bids
+	}
+
+	for _, maker := range resting {
+		if taker.Remaining == 0 {
+			break
+		}
+		if taker.Side == tran.SideBuy && maker.Price > taker.Price ||
+			taker.Side == tran.SideSell && maker.Price < taker.Price {
+			break
+		}
+
+		quantity := taker.Remaining
+		if maker.Remaining < quantity {
+			quantity = maker.Remaining
+		}
+		// the maker's price never exceeds the buyer's limit, so the value is
+		// covered by what the buy order holds.
+		value, _ := s.cost(taker.Base, quantity, maker.Price)
+		if value == 0 {
+			// the value rounds down to nothing, so filling would give the
+			// base units away. Moving on to a worse priced maker would break
+			// price priority, so stop matching and leave the rest resting.
+			break
+		}
+
+		buy, sell := taker, &maker
+		if taker.Side == tran.SideSell {
+			buy, sell = &maker, taker
+		}
+		buy.Remaining -= quantity
+		buy.Locked -= value
+		sell.Remaining -= quantity
+		s.Credit(buy.Owner, buy.Base, quantity)
+		s.Credit(sell.Owner, sell.Quote, value)
+
+		market := Market(taker.Base, taker.Quote)
+		s.trades[market] = append(s.trades[market], Trade{
+			Price:     maker.Price,
+			Quantity:  quantity,
+			Buyer:     buy.Owner,
+			Seller:    sell.Owner,
+			BuyOrder:  buy.ID,
+			SellOrder: sell.ID,
+			Height:    ctx.Height,
+			Time:      ctx.Time,
+		})
+
+		if maker.Remaining == 0 {
+			s.closeOrder(maker)
+		} else {
+			s.orders[maker.ID] = maker
+		}
+	}
+
+	if taker.Remaining == 0 {
+		s.closeOrder(*taker)
+	}
+}
+
+// closeOrder removes an order from the book, returning whatever it still
+// holds to its owner.
+func (s *State) closeOrder(o Order) {
+	delete(s.orders, o.ID)
+	if o.Side == tran.SideSell {
+		if o.Remaining > 0 {
+			s.Credit(o.Owner, o.Base, o.Remaining)
+		}
+	} else if o.Locked > 0 {
+		s.Credit(o.Owner, o.Quote, o.Locked)
+	}
+}
+
+// cancelOrderHandler cancels an open order placed by the sender.
+type cancelOrderHandler struct{}
+
+func (cancelOrderHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	id := p.(*tran.CancelOrder).Order
+	o, ok := s.orders[id]
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: order %s is not open", t.ID, id)
+	}
+	if o.Owner != t.Source {
+		return fmt.Errorf("Transaction invalid: %s: order %s was placed by %s", t.ID, id, o.Owner)
+	}
+	return nil
+}
+
+func (cancelOrderHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	s.closeOrder(s.orders[p.(*tran.CancelOrder).Order])
+}
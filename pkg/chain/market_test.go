@@ -0,0 +1,144 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RKY and LOLA both have 8 decimals.
+const whole = 100000000
+
+func placeOrder(t *testing.T, s *State, owner, side string, price, quantity uint64, height uint64) tran.Transaction {
+	p := tran.PlaceOrder{Base: "RKY", Quote: "LOLA", Side: side, Price: price, Quantity: quantity}
+	o, err := tran.NewPlaceOrderTransaction(owner, p, time.Unix(int64(height), 0).UTC())
+	assert.Nil(t, err)
+	assert.Nil(t, s.ApplyTransaction(o, Context{Height: height}))
+	return o
+}
+
+// TestMatching verifies orders fill at the resting price in price then time
+// priority, and return what they don't spend.
+func TestMatching(t *testing.T) {
+	state := NewState()
+	state.Credit("alice", "RKY", 10*whole)
+	state.Credit("bob", "RKY", 10*whole)
+	state.Credit("carol", "LOLA", 100*whole)
+
+	// asks of 2 RKY at 3 LOLA from alice, then bob, and 2 RKY at 2 LOLA
+	// from bob, which is the best price despite coming last.
+	aliceAsk := placeOrder(t, state, "alice", tran.SideSell, 3*whole, 2*whole, 1)
+	bobAsk := placeOrder(t, state, "bob", tran.SideSell, 3*whole, 2*whole, 2)
+	placeOrder(t, state, "bob", tran.SideSell, 2*whole, 2*whole, 3)
+	assert.Equal(t, uint64(8*whole), state.Balance("alice", "RKY"))
+
+	book := state.OrderBook("RKY", "LOLA")
+	assert.Equal(t, 3, len(book.Asks))
+	assert.Equal(t, uint64(2*whole), book.Asks[0].Price)
+	assert.Equal(t, aliceAsk.ID, book.Asks[1].ID)
+
+	// carol buys 5 RKY paying up to 4 LOLA each: 2 from bob at 2, 2 from
+	// alice at 3 and 1 from bob at 3, leaving bob 1 RKY on the book.
+	placeOrder(t, state, "carol", tran.SideBuy, 4*whole, 5*whole, 4)
+	assert.Equal(t, uint64(5*whole), state.Balance("carol", "RKY"))
+	assert.Equal(t, uint64(87*whole), state.Balance("carol", "LOLA"))
+	assert.Equal(t, uint64(6*whole), state.Balance("alice", "LOLA"))
+	assert.Equal(t, uint64(7*whole), state.Balance("bob", "LOLA"))
+
+	book = state.OrderBook("RKY", "LOLA")
+	assert.Equal(t, 0, len(book.Bids))
+	assert.Equal(t, 1, len(book.Asks))
+	assert.Equal(t, bobAsk.ID, book.Asks[0].ID)
+	assert.Equal(t, uint64(1*whole), book.Asks[0].Remaining)
+
+	trades := state.Trades("RKY", "LOLA")
+	assert.Equal(t, 3, len(trades))
+	assert.Equal(t, uint64(2*whole), trades[0].Price)
+	assert.Equal(t, "alice", trades[1].Seller)
+	assert.Equal(t, "carol", trades[2].Buyer)
+}
+
+// TestRestingBuyAndCancel verifies a buy that doesn't cross rests on the
+// book holding its funds, and that cancelling returns them.
+func TestRestingBuyAndCancel(t *testing.T) {
+	state := NewState()
+	state.Credit("alice", "RKY", 10*whole)
+	state.Credit("carol", "LOLA", 10*whole)
+
+	bid := placeOrder(t, state, "carol", tran.SideBuy, 2*whole, 4*whole, 1)
+	assert.Equal(t, uint64(2*whole), state.Balance("carol", "LOLA"))
+
+	// alice sells below the bid and is paid the bid's price.
+	placeOrder(t, state, "alice", tran.SideSell, 1*whole, 1*whole, 2)
+	assert.Equal(t, uint64(2*whole), state.Balance("alice", "LOLA"))
+	assert.Equal(t, uint64(1*whole), state.Balance("carol", "RKY"))
+
+	cancel, err := tran.NewCancelOrderTransaction("alice", bid.ID, time.Unix(3, 0).UTC())
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(cancel, Context{Height: 3}))
+
+	cancel, err = tran.NewCancelOrderTransaction("carol", bid.ID, time.Unix(3, 0).UTC())
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(cancel, Context{Height: 3}))
+	assert.Equal(t, uint64(8*whole), state.Balance("carol", "LOLA"))
+	assert.Equal(t, 0, len(state.OrderBook("RKY", "LOLA").Bids))
+}
+
+// TestOrderFunds verifies orders can't be placed beyond the sender's balance.
+func TestOrderFunds(t *testing.T) {
+	state := NewState()
+	state.Credit("carol", "LOLA", 1*whole)
+
+	p := tran.PlaceOrder{Base: "RKY", Quote: "LOLA", Side: tran.SideBuy, Price: 2 * whole, Quantity: 1 * whole}
+	o, err := tran.NewPlaceOrderTransaction("carol", p, time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(o, Context{}))
+
+	p = tran.PlaceOrder{Base: "RKY", Quote: "LOLA", Side: tran.SideSell, Price: 2 * whole, Quantity: 1}
+	o, err = tran.NewPlaceOrderTransaction("carol", p, time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(o, Context{}))
+}
+
+// TestDustFill verifies a fill whose value rounds down to nothing is skipped
+// rather than giving the base units away.
+func TestDustFill(t *testing.T) {
+	state := NewState()
+	state.Credit("alice", "RKY", 1)
+	state.Credit("carol", "LOLA", 10)
+
+	placeOrder(t, state, "carol", tran.SideBuy, 1, 2*whole, 1)
+	ask := placeOrder(t, state, "alice", tran.SideSell, 1, 1, 2)
+	assert.Equal(t, uint64(0), state.Balance("carol", "RKY"))
+	assert.Empty(t, state.Trades("RKY", "LOLA"))
+
+	book := state.OrderBook("RKY", "LOLA")
+	assert.Equal(t, 1, len(book.Bids))
+	assert.Equal(t, 1, len(book.Asks))
+	assert.Equal(t, ask.ID, book.Asks[0].ID)
+}
+
+// TestDustFillKeepsPriority verifies a worse priced maker isn't filled when
+// the fill against a better priced one rounds down to nothing.
+func TestDustFillKeepsPriority(t *testing.T) {
+	state := NewState()
+	state.Credit("alice", "RKY", 1)
+	state.Credit("bob", "RKY", 1*whole)
+	state.Credit("carol", "LOLA", 10*whole)
+
+	placeOrder(t, state, "alice", tran.SideSell, 1, 1, 1)
+	placeOrder(t, state, "bob", tran.SideSell, 2*whole, 1*whole, 2)
+	bid := placeOrder(t, state, "carol", tran.SideBuy, 2*whole, 1*whole, 3)
+
+	assert.Equal(t, uint64(0), state.Balance("carol", "RKY"))
+	assert.Equal(t, uint64(0), state.Balance("bob", "LOLA"))
+	assert.Empty(t, state.Trades("RKY", "LOLA"))
+
+	book := state.OrderBook("RKY", "LOLA")
+	assert.Equal(t, 2, len(book.Asks))
+	assert.Equal(t, 1, len(book.Bids))
+	assert.Equal(t, bid.ID, book.Bids[0].ID)
+}
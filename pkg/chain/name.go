@@ -0,0 +1,107 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// Registering or renewing a name burns NameFee base units of NameFeeSymbol
+// for every block of its term, so holding names costs something and a
+// squatter can't claim names in bulk for free.
+const (
+	NameFeeSymbol = "RKY"
+	NameFee       = 1000
+)
+
+func init() {
+	RegisterHandler(tran.TypeRegisterName, registerNameHandler{})
+	RegisterHandler(tran.TypeTransferName, transferNameHandler{})
+}
+
+// NameRecord is a registered name. It resolves to Target until the chain
+// reaches Expires, after which anyone may register it.
+type NameRecord struct {
+	Name    string `json:"name"`
+	Owner   string `json:"owner"`
+	Target  string `json:"target"`
+	Expires uint64 `json:"expires"`
+}
+
+// ResolveName returns the record for name if it is registered and unexpired
+// at height.
+func (s *State) ResolveName(name string, height uint64) (NameRecord, bool) {
+	r, ok := s.names[name]
+	if !ok || height >= r.Expires {
+		return NameRecord{}, false
+	}
+	return r, true
+}
+
+// registerNameHandler registers a free name to the sender, or renews and
+// repoints a name the sender owns, burning the fee for the term.
+type registerNameHandler struct{}
+
+func (registerNameHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	reg := p.(*tran.RegisterName)
+	if r, ok := s.ResolveName(reg.Name, ctx.Height); ok && r.Owner != t.Source {
+		return fmt.Errorf("Transaction invalid: %s: name %s is owned by %s", t.ID, reg.Name, r.Owner)
+	}
+	if s.Balance(t.Source, NameFeeSymbol) < reg.Term*NameFee {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	return nil
+}
+
+func (registerNameHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	reg := p.(*tran.RegisterName)
+
+	s.Burn(t.Source, NameFeeSymbol, reg.Term*NameFee)
+
+	// a renewal extends the current term rather than restarting it.
+	start := ctx.Height
+	if r, ok := s.ResolveName(reg.Name, ctx.Height); ok {
+		start = r.Expires
+	}
+	s.names[reg.Name] = NameRecord{
+		Name:    reg.Name,
+		Owner:   t.Source,
+		Target:  reg.Target,
+		Expires: start + reg.Term,
+	}
+}
+
+// transferNameHandler hands a name to a new owner, pointing it at the new
+// owner's address.
+type transferNameHandler struct{}
+
+func (transferNameHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	name := p.(*tran.TransferName).Name
+	r, ok := s.ResolveName(name, ctx.Height)
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: name %s is not registered", t.ID, name)
+	}
+	if r.Owner != t.Source {
+		return fmt.Errorf("Transaction invalid: %s: name %s is owned by %s", t.ID, name, r.Owner)
+	}
+	return nil
+}
+
+func (transferNameHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	transfer := p.(*tran.TransferName)
+
+	r := s.names[transfer.Name]
+	r.Owner = transfer.Owner
+	r.Target = transfer.Owner
+	s.names[transfer.Name] = r
+}
@@ -0,0 +1,65 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNames verifies names resolve until they expire, can be renewed and
+// transferred by their owner, and are free again once expired. Each
+// registration burns the fee for its term.
+func TestNames(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	state := NewState()
+	state.Credit("alice", NameFeeSymbol, 25*NameFee)
+	state.Credit("mallory", NameFeeSymbol, 10*NameFee)
+
+	register, err := tran.NewRegisterNameTransaction("alice", "lola.dog", "alice-savings", 10, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(register, Context{Height: 1}))
+	assert.Equal(t, uint64(15*NameFee), state.Balance("alice", NameFeeSymbol))
+
+	r, ok := state.ResolveName("lola.dog", 10)
+	assert.True(t, ok)
+	assert.Equal(t, "alice-savings", r.Target)
+	assert.Equal(t, uint64(11), r.Expires)
+	_, ok = state.ResolveName("lola.dog", 11)
+	assert.False(t, ok)
+
+	squat, err := tran.NewRegisterNameTransaction("mallory", "lola.dog", "mallory", 10, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(squat, Context{Height: 5}))
+
+	renew, err := tran.NewRegisterNameTransaction("alice", "lola.dog", "alice", 10, tm.Add(time.Second))
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(renew, Context{Height: 5}))
+	r, _ = state.ResolveName("lola.dog", 5)
+	assert.Equal(t, "alice", r.Target)
+	assert.Equal(t, uint64(21), r.Expires)
+	assert.Equal(t, uint64(5*NameFee), state.Balance("alice", NameFeeSymbol))
+
+	unpaid, err := tran.NewRegisterNameTransaction("alice", "lola.cat", "alice", 10, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(unpaid, Context{Height: 5}))
+
+	steal, err := tran.NewTransferNameTransaction("mallory", "lola.dog", "mallory", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(steal, Context{Height: 6}))
+
+	transfer, err := tran.NewTransferNameTransaction("alice", "lola.dog", "bob", tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(transfer, Context{Height: 6}))
+	r, _ = state.ResolveName("lola.dog", 6)
+	assert.Equal(t, "bob", r.Owner)
+	assert.Equal(t, "bob", r.Target)
+
+	assert.Nil(t, state.ApplyTransaction(squat, Context{Height: 21}))
+	r, _ = state.ResolveName("lola.dog", 21)
+	assert.Equal(t, "mallory", r.Owner)
+	info, _ := state.Token(NameFeeSymbol)
+	assert.Equal(t, uint64(30*NameFee), info.Supply.Burned)
+}
@@ -0,0 +1,125 @@
+package chain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+func init() {
+	RegisterHandler(tran.TypeMintNFT, mintNFTHandler{})
+	RegisterHandler(tran.TypeTransferNFT, transferNFTHandler{})
+}
+
+// NFT is a unique token.
+type NFT struct {
+	ID       string `json:"id"`
+	Owner    string `json:"owner"`
+	Creator  string `json:"creator"`
+	Metadata string `json:"metadata"`
+}
+
+// NFTTransfer is one change of ownership of a unique token. Its mint has an
+// empty From.
+type NFTTransfer struct {
+	From        string    `json:"from,omitempty"`
+	To          string    `json:"to"`
+	Transaction string    `json:"transaction"`
+	Height      uint64    `json:"height"`
+	Time        time.Time `json:"time"`
+}
+
+// NFT returns the unique token with the supplied id.
+func (s *State) NFT(id string) (NFT, bool) {
+	n, ok := s.nfts[id]
+	return n, ok
+}
+
+// NFTHistory returns every change of ownership of the unique token id, oldest
+// first.
+func (s *State) NFTHistory(id string) []NFTTransfer {
+	return append([]NFTTransfer{}, s.nftHistory[id]...)
+}
+
+// NFTsOwnedBy returns the unique tokens owned by address, sorted by ID.
+func (s *State) NFTsOwnedBy(address string) []NFT {
+	owned := []NFT{}
+	for _, n := range s.nfts {
+		if n.Owner == address {
+			owned = append(owned, n)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool { return owned[i].ID < owned[j].ID })
+	return owned
+}
+
+func (s *State) recordNFTTransfer(id, from, to string, t tran.Transaction, ctx Context) {
+	s.nftHistory[id] = append(s.nftHistory[id], NFTTransfer{
+		From:        from,
+		To:          to,
+		Transaction: t.ID,
+		Height:      ctx.Height,
+		Time:        ctx.Time,
+	})
+}
+
+// mintNFTHandler creates a unique token owned by its creator.
+type mintNFTHandler struct{}
+
+func (mintNFTHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	id := p.(*tran.MintNFT).ID
+	if _, ok := s.nfts[id]; ok {
+		return fmt.Errorf("Transaction invalid: %s: token %s already exists", t.ID, id)
+	}
+	return nil
+}
+
+func (mintNFTHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	mint := p.(*tran.MintNFT)
+
+	s.nfts[mint.ID] = NFT{
+		ID:       mint.ID,
+		Owner:    t.Source,
+		Creator:  t.Source,
+		Metadata: hex.EncodeToString(mint.Metadata),
+	}
+	s.recordNFTTransfer(mint.ID, "", t.Source, t, ctx)
+}
+
+// transferNFTHandler gives a unique token owned by the sender to the
+// destination.
+type transferNFTHandler struct{}
+
+func (transferNFTHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	id := p.(*tran.TransferNFT).ID
+	n, ok := s.nfts[id]
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: unknown token %s", t.ID, id)
+	}
+	if n.Owner != t.Source {
+		return fmt.Errorf("Transaction invalid: %s: token %s is owned by %s", t.ID, id, n.Owner)
+	}
+	return nil
+}
+
+func (transferNFTHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	id := p.(*tran.TransferNFT).ID
+
+	n := s.nfts[id]
+	n.Owner = t.Destination
+	s.nfts[id] = n
+	s.recordNFTTransfer(id, t.Source, t.Destination, t, ctx)
+}
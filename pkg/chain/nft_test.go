@@ -0,0 +1,50 @@
+package chain
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNFT verifies unique tokens can only be minted once and moved by their
+// owner, and that their history follows every transfer.
+func TestNFT(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	metadata := sha256.Sum256([]byte("lola.jpg"))
+	state := NewState()
+
+	mint, err := tran.NewMintNFTTransaction("alice", "lola-1", metadata[:], tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(mint, Context{Height: 1}))
+
+	again, err := tran.NewMintNFTTransaction("bob", "lola-1", metadata[:], tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(again, Context{Height: 2}))
+
+	steal, err := tran.NewTransferNFTTransaction("bob", "bob", "lola-1", "", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(steal, Context{Height: 2}))
+
+	give, err := tran.NewTransferNFTTransaction("alice", "bob", "lola-1", "for you", tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(give, Context{Height: 3}))
+
+	n, ok := state.NFT("lola-1")
+	assert.True(t, ok)
+	assert.Equal(t, "bob", n.Owner)
+	assert.Equal(t, "alice", n.Creator)
+	assert.Equal(t, 0, len(state.NFTsOwnedBy("alice")))
+	assert.Equal(t, []NFT{n}, state.NFTsOwnedBy("bob"))
+
+	history := state.NFTHistory("lola-1")
+	assert.Equal(t, 2, len(history))
+	assert.Equal(t, "", history[0].From)
+	assert.Equal(t, "alice", history[0].To)
+	assert.Equal(t, "alice", history[1].From)
+	assert.Equal(t, "bob", history[1].To)
+	assert.Equal(t, uint64(3), history[1].Height)
+}
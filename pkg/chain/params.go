@@ -0,0 +1,143 @@
+package chain
+
+import (
+	"github.com/datravis/lolachain/pkg/block"
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/token"
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// Params are the consensus rules in force from an activation height onwards.
+// Reward, Difficulty and MaxBlockSize may also be changed by governance; see
+// State.Params.
+type Params struct {
+	// Height is the index of the first block the rules apply to.
+	Height uint64 `json:"height"`
+
+	// BlockVersion is the version every block must carry, which also selects
+	// how block headers are hashed.
+	BlockVersion uint8 `json:"block_version"`
+
+	// Reward is the number of whole tokens of each reward symbol minted to
+	// the validator of a block.
+	Reward        uint64   `json:"reward"`
+	RewardSymbols []string `json:"reward_symbols"`
+
+	// Difficulty is the divisor every block's incrementor must be a multiple
+	// of, apart from the genesis block's.
+	Difficulty uint64 `json:"difficulty"`
+
+	// MaxBlockSize is the largest encoded size, in bytes, of a block.
+	MaxBlockSize uint64 `json:"max_block_size"`
+
+	// ScriptAddresses reports whether script addresses may be used as a
+	// transaction's source or destination.
+	ScriptAddresses bool `json:"script_addresses"`
+
+	// MultisigAddresses reports whether multisig addresses may be used as a
+	// transaction's source or destination.
+	MultisigAddresses bool `json:"multisig_addresses"`
+
+	// TransactionTypes lists the transaction types that may appear in a
+	// block.
+	TransactionTypes []string `json:"transaction_types"`
+}
+
+// Forks lists the consensus rules in order of activation height. New rules
+// are added with an activation height in the future, so validators running
+// old and new code agree on every block until then.
+var Forks = []Params{
+	{
+		Height:           0,
+		BlockVersion:     block.Version1,
+		Reward:           1,
+		RewardSymbols:    []string{"RKY", "LOLA"},
+		Difficulty:       INCREMENTOR_DIVISOR,
+		MaxBlockSize:     block.MaxBlockSize,
+		TransactionTypes: []string{tran.TypeTransfer, tran.TypeCoinbase},
+	},
+	{
+		// activates script and multisig addresses, staking, tokens,
+		// contracts, names, anchors, collectibles, the exchange, allowances,
+		// channels, stealth transfers and governance.
+		Height:            20000,
+		BlockVersion:      block.Version1,
+		Reward:            1,
+		RewardSymbols:     []string{"RKY", "LOLA"},
+		Difficulty:        INCREMENTOR_DIVISOR,
+		MaxBlockSize:      block.MaxBlockSize,
+		ScriptAddresses:   true,
+		MultisigAddresses: true,
+		TransactionTypes: []string{
+			tran.TypeTransfer, tran.TypeCoinbase,
+			tran.TypeStake, tran.TypeUnstake,
+			tran.TypeIssueToken, tran.TypeMint, tran.TypeBurn,
+			tran.TypeLockedTransfer,
+			tran.TypeHTLC, tran.TypeHTLCRedeem, tran.TypeHTLCRefund,
+			tran.TypeBatch,
+			tran.TypeRegisterName, tran.TypeTransferName,
+			tran.TypeAnchor,
+			tran.TypeMintNFT, tran.TypeTransferNFT,
+			tran.TypePlaceOrder, tran.TypeCancelOrder,
+			tran.TypeApprove, tran.TypeSpendAllowance,
+			tran.TypeOpenChannel, tran.TypeCloseChannel, tran.TypeSettleChannel,
+			tran.TypeStealthTransfer,
+			tran.TypeProposeParam, tran.TypeVote,
+		},
+	},
+}
+
+// ParamsAt returns the consensus rules for the block at height.
+func ParamsAt(height uint64) Params {
+	params := Forks[0]
+	for _, p := range Forks {
+		if p.Height <= height {
+			params = p
+		}
+	}
+	return params
+}
+
+// AllowsAddresses reports whether the source and destination of t are kinds
+// of address that may appear in a block under these rules.
+func (p Params) AllowsAddresses(t tran.Transaction) bool {
+	for _, address := range []string{t.Source, t.Destination} {
+		if !p.ScriptAddresses && keys.IsScriptAddress(address) {
+			return false
+		}
+		if !p.MultisigAddresses && keys.IsMultisigAddress(address) {
+			return false
+		}
+	}
+	return true
+}
+
+// BlockReward returns the scheduled coinbase amount of symbol for a block
+// under these rules in base units, and false if the symbol is not a block
+// reward.
+func (p Params) BlockReward(symbol string) (uint64, bool) {
+	for _, s := range p.RewardSymbols {
+		if s == symbol {
+			tk, ok := token.Lookup(symbol)
+			if !ok {
+				return 0, false
+			}
+			return tk.Units(p.Reward), true
+		}
+	}
+	return 0, false
+}
+
+// AllowsType reports whether transactions of type txType may appear in a
+// block under these rules.
+func (p Params) AllowsType(txType string) bool {
+	if txType == "" {
+		txType = tran.TypeTransfer
+	}
+	for _, t := range p.TransactionTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package chain
+
+import (
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParamsAt verifies rules switch over at their activation height.
+func TestParamsAt(t *testing.T) {
+	defer func(forks []Params) { Forks = forks }(Forks)
+
+	last := Forks[len(Forks)-1]
+	next := last
+	next.Height = last.Height + 100
+	next.Reward = 2
+	next.TransactionTypes = []string{tran.TypeCoinbase}
+	Forks = append(Forks, next)
+
+	assert.Equal(t, uint64(1), ParamsAt(0).Reward)
+	assert.Equal(t, uint64(1), ParamsAt(next.Height-1).Reward)
+	assert.Equal(t, uint64(2), ParamsAt(next.Height).Reward)
+	assert.Equal(t, uint64(2), ParamsAt(next.Height+1000).Reward)
+
+	assert.True(t, ParamsAt(next.Height-1).AllowsType(""))
+	assert.False(t, ParamsAt(next.Height).AllowsType(tran.TypeTransfer))
+	assert.True(t, ParamsAt(next.Height).AllowsType(tran.TypeCoinbase))
+}
+
+// TestGenesisRules verifies the genesis rules only allow the original
+// transaction types, with later types activated by a fork in the future.
+func TestGenesisRules(t *testing.T) {
+	assert.Equal(t, []string{tran.TypeTransfer, tran.TypeCoinbase}, Forks[0].TransactionTypes)
+	assert.False(t, ParamsAt(0).AllowsType(tran.TypeStake))
+
+	fork := Forks[1]
+	assert.True(t, fork.Height > 0)
+	assert.False(t, ParamsAt(fork.Height-1).AllowsType(tran.TypeStake))
+	assert.True(t, ParamsAt(fork.Height).AllowsType(tran.TypeStake))
+	assert.True(t, ParamsAt(fork.Height).AllowsType(tran.TypeVote))
+}
+
+// TestScriptAddressFork verifies script addresses are only allowed once the
+// fork activating them is reached.
+func TestScriptAddressFork(t *testing.T) {
+	lock := []byte{0x01}
+	tx, err := tran.NewTransaction("RKY", keys.ScriptAddress(lock), "dest", 1, "", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+
+	assert.False(t, ParamsAt(0).AllowsAddresses(tx))
+	assert.True(t, ParamsAt(Forks[1].Height).AllowsAddresses(tx))
+
+	tx.Source, tx.Destination = "source", keys.ScriptAddress(lock)
+	assert.False(t, ParamsAt(0).AllowsAddresses(tx))
+
+	tx.Destination = "dest"
+	assert.True(t, ParamsAt(0).AllowsAddresses(tx))
+}
+
+// TestMultisigAddressFork verifies transfers from or to a multisig address
+// are only allowed once the fork activating them is reached.
+func TestMultisigAddressFork(t *testing.T) {
+	key, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	m, err := keys.NewMultisig(1, []*ecdsa.PublicKey{&key.PublicKey})
+	assert.Nil(t, err)
+	address, err := m.Address()
+	assert.Nil(t, err)
+
+	tx, err := tran.NewTransaction("RKY", address, "dest", 1, "", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+	assert.False(t, ParamsAt(0).AllowsAddresses(tx))
+	assert.False(t, ParamsAt(Forks[1].Height-1).AllowsAddresses(tx))
+	assert.True(t, ParamsAt(Forks[1].Height).AllowsAddresses(tx))
+
+	tx.Source, tx.Destination = "source", address
+	assert.False(t, ParamsAt(Forks[1].Height-1).AllowsAddresses(tx))
+	assert.True(t, ParamsAt(Forks[1].Height).AllowsAddresses(tx))
+}
@@ -0,0 +1,50 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+func init() {
+	RegisterHandler(tran.TypeStake, stakeHandler{})
+	RegisterHandler(tran.TypeUnstake, unstakeHandler{})
+}
+
+// Stake returns the amount of symbol staked by address, in base units.
+func (s *State) Stake(address, symbol string) uint64 {
+	return s.stakes[address][symbol]
+}
+
+// stakeHandler moves funds from an address's balance into its stake.
+type stakeHandler struct{}
+
+func (stakeHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if s.Balance(t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	return nil
+}
+
+func (stakeHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	s.Debit(t.Source, t.Symbol, t.Amount)
+	if _, ok := s.stakes[t.Source]; !ok {
+		s.stakes[t.Source] = make(map[string]uint64)
+	}
+	s.stakes[t.Source][t.Symbol] += t.Amount
+}
+
+// unstakeHandler returns staked funds to an address's balance.
+type unstakeHandler struct{}
+
+func (unstakeHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if s.Stake(t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient stake to perform transaction")
+	}
+	return nil
+}
+
+func (unstakeHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	s.stakes[t.Source][t.Symbol] -= t.Amount
+	s.Credit(t.Source, t.Symbol, t.Amount)
+}
@@ -0,0 +1,143 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/datravis/lolachain/pkg/block"
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/token"
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// State is the ledger produced by applying blocks in order. Each transaction
+// type updates it through the Handler registered for the type.
+type State struct {
+	tokens     map[string]TokenInfo
+	balances   map[string]map[string]uint64
+	stakes     map[string]map[string]uint64
+	locks      map[string][]LockedFunds
+	contracts  map[string]Contract
+	names      map[string]NameRecord
+	anchors    map[string]AnchorRecord
+	nfts       map[string]NFT
+	nftHistory map[string][]NFTTransfer
+	orders     map[string]Order
+	orderSeq   uint64
+	trades     map[string][]Trade
+	allowances map[string]map[string]map[string]uint64
+	channels   map[string]Channel
+	proposals  map[string]Proposal
+	approved   []Proposal
+	applied    map[string]bool
+}
+
+// NewState returns an empty ledger holding only the builtin tokens.
+func NewState() *State {
+	s := &State{
+		tokens:     make(map[string]TokenInfo),
+		balances:   make(map[string]map[string]uint64),
+		stakes:     make(map[string]map[string]uint64),
+		locks:      make(map[string][]LockedFunds),
+		contracts:  make(map[string]Contract),
+		names:      make(map[string]NameRecord),
+		anchors:    make(map[string]AnchorRecord),
+		nfts:       make(map[string]NFT),
+		nftHistory: make(map[string][]NFTTransfer),
+		orders:     make(map[string]Order),
+		trades:     make(map[string][]Trade),
+		allowances: make(map[string]map[string]map[string]uint64),
+		channels:   make(map[string]Channel),
+		proposals:  make(map[string]Proposal),
+		applied:    make(map[string]bool),
+	}
+	for _, t := range token.Builtins() {
+		s.tokens[t.Symbol] = TokenInfo{Token: t}
+	}
+	return s
+}
+
+// Balance returns the spendable balance of symbol held by address, in base
+// units. Locked funds are not included.
+func (s *State) Balance(address, symbol string) uint64 {
+	return s.balances[address][symbol]
+}
+
+// Balances returns a copy of every balance held by address, in base units.
+func (s *State) Balances(address string) map[string]uint64 {
+	balances := make(map[string]uint64)
+	for symbol, amount := range s.balances[address] {
+		balances[symbol] = amount
+	}
+	return balances
+}
+
+// Credit adds amount of symbol to the balance of address.
+func (s *State) Credit(address, symbol string, amount uint64) {
+	if _, ok := s.balances[address]; !ok {
+		s.balances[address] = make(map[string]uint64)
+	}
+	s.balances[address][symbol] += amount
+}
+
+// Debit removes amount of symbol from the balance of address. Handlers must
+// check the balance is sufficient first.
+func (s *State) Debit(address, symbol string, amount uint64) {
+	if _, ok := s.balances[address]; !ok {
+		s.balances[address] = make(map[string]uint64)
+	}
+	s.balances[address][symbol] -= amount
+}
+
+// CheckTransaction reports whether t can be applied to the ledger in ctx. A
+// transaction from a script address must satisfy the address's script in
+// ctx.
+func (s *State) CheckTransaction(t tran.Transaction, ctx Context) error {
+	if s.applied[t.ID] {
+		return fmt.Errorf("Transaction invalid: %s: already applied", t.ID)
+	}
+
+	h, ok := handlers[t.TransactionType()]
+	if !ok {
+		return fmt.Errorf("Transaction invalid: %s: no handler for type %s", t.ID, t.TransactionType())
+	}
+	if keys.IsScriptAddress(t.Source) {
+		if err := t.VerifyScript(ctx.Height, ctx.Time); err != nil {
+			return fmt.Errorf("Transaction invalid: %s: %s", t.ID, err)
+		}
+	}
+	if rules, _ := tran.LookupType(t.TransactionType()); rules.Amount {
+		if _, ok := s.tokens[t.Symbol]; !ok {
+			return fmt.Errorf("Transaction invalid: %s: unknown symbol %s", t.ID, t.Symbol)
+		}
+	}
+	return h.Check(s, t, ctx)
+}
+
+// ApplyTransaction checks t and, if it is valid, applies it to the ledger.
+func (s *State) ApplyTransaction(t tran.Transaction, ctx Context) error {
+	if err := s.CheckTransaction(t, ctx); err != nil {
+		return err
+	}
+
+	handlers[t.TransactionType()].Apply(s, t, ctx)
+	s.applied[t.ID] = true
+
+	return nil
+}
+
+// ApplyBlock releases the locks that have expired by b and applies every
+// transaction of b in order. Transactions within a block may spend funds
+// received earlier in the same block, but never more. The ledger is left
+// partially updated if an error is returned.
+func (s *State) ApplyBlock(b *block.Block) error {
+	ctx := Context{Height: b.Index, Time: b.Time, Validator: b.Validator}
+	s.Unlock(ctx)
+	s.Tally(ctx)
+	for _, t := range b.Transactions {
+		if err := s.ApplyTransaction(t, ctx); err != nil {
+			return fmt.Errorf("Block invalid: %d: %s", b.Index, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package chain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/block"
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/script"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyBlockOverspend verifies transactions in one block can't jointly spend more than the sender holds.
+func TestApplyBlockOverspend(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	reward, err := tran.NewCoinbaseTransaction("RKY", "alice", 10, tm)
+	assert.Nil(t, err)
+	first, err := tran.NewTransaction("RKY", "alice", "bob", 6, "first", tm)
+	assert.Nil(t, err)
+	second, err := tran.NewTransaction("RKY", "alice", "carol", 6, "second", tm)
+	assert.Nil(t, err)
+
+	state := NewState()
+	assert.Nil(t, state.ApplyBlock(&block.Block{Index: 0, Validator: "alice", Transactions: []tran.Transaction{reward}}))
+	assert.Equal(t, uint64(10), state.Balance("alice", "RKY"))
+
+	// each transaction is affordable alone, but not together.
+	assert.Nil(t, state.CheckTransaction(first, Context{}))
+	assert.Nil(t, state.CheckTransaction(second, Context{}))
+	assert.NotNil(t, state.ApplyBlock(&block.Block{Index: 1, Validator: "alice", Transactions: []tran.Transaction{first, second}}))
+}
+
+// TestApplyTransactionReplay verifies a transaction can only be applied once.
+func TestApplyTransactionReplay(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	reward, err := tran.NewCoinbaseTransaction("RKY", "alice", 10, tm)
+	assert.Nil(t, err)
+	send, err := tran.NewTransaction("RKY", "alice", "bob", 1, "memo", tm)
+	assert.Nil(t, err)
+
+	ctx := Context{Validator: "alice"}
+	state := NewState()
+	assert.Nil(t, state.ApplyTransaction(reward, ctx))
+	assert.Nil(t, state.ApplyTransaction(send, ctx))
+	assert.NotNil(t, state.ApplyTransaction(send, ctx))
+	assert.Equal(t, uint64(9), state.Balance("alice", "RKY"))
+	assert.Equal(t, uint64(1), state.Balance("bob", "RKY"))
+}
+
+// TestStake verifies staked funds leave the spendable balance until unstaked.
+func TestStake(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	reward, err := tran.NewCoinbaseTransaction("RKY", "alice", 10, tm)
+	assert.Nil(t, err)
+	stake, err := tran.NewStakeTransaction("RKY", "alice", 7, tm)
+	assert.Nil(t, err)
+	send, err := tran.NewTransaction("RKY", "alice", "bob", 5, "memo", tm)
+	assert.Nil(t, err)
+	unstake, err := tran.NewUnstakeTransaction("RKY", "alice", 8, tm)
+	assert.Nil(t, err)
+
+	ctx := Context{Validator: "alice"}
+	state := NewState()
+	assert.Nil(t, state.ApplyTransaction(reward, ctx))
+	assert.Nil(t, state.ApplyTransaction(stake, ctx))
+	assert.Equal(t, uint64(3), state.Balance("alice", "RKY"))
+	assert.Equal(t, uint64(7), state.Stake("alice", "RKY"))
+
+	assert.NotNil(t, state.ApplyTransaction(send, ctx))
+	assert.NotNil(t, state.ApplyTransaction(unstake, ctx))
+
+	unstake, err = tran.NewUnstakeTransaction("RKY", "alice", 7, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(unstake, ctx))
+	assert.Nil(t, state.ApplyTransaction(send, ctx))
+	assert.Equal(t, uint64(5), state.Balance("alice", "RKY"))
+}
+
+// TestScriptSpend verifies funds at a script address can only be spent once
+// its script is satisfied in the block context.
+func TestScriptSpend(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	inner, err := script.PayToKey(&k.PublicKey)
+	assert.Nil(t, err)
+	vault := keys.ScriptAddress(script.AfterHeight(5, inner))
+
+	state := NewState()
+	state.Credit(vault, "RKY", 10)
+
+	spend, err := tran.NewTransaction("RKY", vault, "bob", 4, "memo", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(spend, Context{Height: 5}))
+
+	sig, err := script.Sign(k, spend.SignatureDigest())
+	assert.Nil(t, err)
+	spend.Witness = new(script.Builder).Push(sig).Script()
+	assert.NotNil(t, state.CheckTransaction(spend, Context{Height: 4}))
+	assert.Nil(t, state.ApplyTransaction(spend, Context{Height: 5}))
+	assert.Equal(t, uint64(4), state.Balance("bob", "RKY"))
+}
@@ -0,0 +1,126 @@
+package chain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/datravis/lolachain/pkg/token"
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// TokenInfo describes a token that exists on the chain.
+type TokenInfo struct {
+	token.Token
+	Issuer        string       `json:"issuer,omitempty"`
+	MintAuthority string       `json:"mint_authority,omitempty"`
+	Supply        token.Supply `json:"supply"`
+}
+
+func init() {
+	RegisterHandler(tran.TypeIssueToken, issueTokenHandler{})
+	RegisterHandler(tran.TypeMint, mintHandler{})
+	RegisterHandler(tran.TypeBurn, burnHandler{})
+}
+
+// Mint credits newly created units of symbol to address. It doesn't check
+// for overflow: the circulating supply and every balance are at most the
+// minted supply, so handlers only need to check that the minted supply plus
+// amount fits, as mintHandler does. Block rewards are bounded by the schedule
+// and an issued token starts with nothing minted.
+func (s *State) Mint(address, symbol string, amount uint64) {
+	info := s.tokens[symbol]
+	info.Supply.Minted += amount
+	info.Supply.Circulating += amount
+	s.tokens[symbol] = info
+
+	s.Credit(address, symbol, amount)
+}
+
+// Burn destroys units of symbol held by address. Handlers must check the
+// balance is sufficient first.
+func (s *State) Burn(address, symbol string, amount uint64) {
+	s.Debit(address, symbol, amount)
+
+	info := s.tokens[symbol]
+	info.Supply.Burned += amount
+	info.Supply.Circulating -= amount
+	s.tokens[symbol] = info
+}
+
+// Token returns the token with the supplied symbol.
+func (s *State) Token(symbol string) (TokenInfo, bool) {
+	info, ok := s.tokens[symbol]
+	return info, ok
+}
+
+// Tokens returns every token on the chain, ordered by symbol.
+func (s *State) Tokens() []TokenInfo {
+	tokens := make([]TokenInfo, 0, len(s.tokens))
+	for _, info := range s.tokens {
+		tokens = append(tokens, info)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Symbol < tokens[j].Symbol })
+	return tokens
+}
+
+// issueTokenHandler creates a token and credits its initial supply to the
+// issuer.
+type issueTokenHandler struct{}
+
+func (issueTokenHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	p, err := t.DecodePayload()
+	if err != nil {
+		return err
+	}
+	issue := p.(*tran.IssueToken)
+	if _, ok := s.tokens[issue.Symbol]; ok {
+		return fmt.Errorf("Transaction invalid: %s: token %s already exists", t.ID, issue.Symbol)
+	}
+	return nil
+}
+
+func (issueTokenHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	p, _ := t.DecodePayload()
+	issue := p.(*tran.IssueToken)
+
+	s.tokens[issue.Symbol] = TokenInfo{
+		Token:         token.Token{Symbol: issue.Symbol, Name: issue.Name, Decimals: issue.Decimals},
+		Issuer:        t.Source,
+		MintAuthority: issue.MintAuthority,
+	}
+	if issue.Supply > 0 {
+		s.Mint(t.Source, issue.Symbol, issue.Supply)
+	}
+}
+
+// mintHandler lets a token's mint authority create more of it.
+type mintHandler struct{}
+
+func (mintHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	info := s.tokens[t.Symbol]
+	if info.MintAuthority == "" || info.MintAuthority != t.Source {
+		return fmt.Errorf("Transaction invalid: %s: %s is not the mint authority of %s", t.ID, t.Source, t.Symbol)
+	}
+	if info.Supply.Minted+t.Amount < info.Supply.Minted || s.Balance(t.Destination, t.Symbol)+t.Amount < t.Amount {
+		return fmt.Errorf("Transaction invalid: %s: minting %d %s overflows its supply", t.ID, t.Amount, t.Symbol)
+	}
+	return nil
+}
+
+func (mintHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	s.Mint(t.Destination, t.Symbol, t.Amount)
+}
+
+// burnHandler removes tokens from circulation.
+type burnHandler struct{}
+
+func (burnHandler) Check(s *State, t tran.Transaction, ctx Context) error {
+	if s.Balance(t.Source, t.Symbol) < t.Amount {
+		return fmt.Errorf("Insufficient funds to perform transaction")
+	}
+	return nil
+}
+
+func (burnHandler) Apply(s *State, t tran.Transaction, ctx Context) {
+	s.Burn(t.Source, t.Symbol, t.Amount)
+}
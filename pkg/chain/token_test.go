@@ -0,0 +1,100 @@
+package chain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/token"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIssueAndMint verifies issued tokens exist, credit the issuer and can only be minted by their authority.
+func TestIssueAndMint(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	ctx := Context{}
+	state := NewState()
+
+	send, err := tran.NewTransaction("DOG", "alice", "bob", 1, "memo", tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.CheckTransaction(send, ctx))
+
+	issue, err := tran.NewIssueTokenTransaction("alice", tran.IssueToken{Symbol: "DOG", Name: "DogCoin", Decimals: 2, Supply: 100, MintAuthority: "alice"}, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(issue, ctx))
+
+	info, ok := state.Token("DOG")
+	assert.True(t, ok)
+	assert.Equal(t, "DogCoin", info.Name)
+	assert.Equal(t, "alice", info.Issuer)
+	assert.Equal(t, uint64(100), state.Balance("alice", "DOG"))
+	assert.Nil(t, state.ApplyTransaction(send, ctx))
+
+	again, err := tran.NewIssueTokenTransaction("bob", tran.IssueToken{Symbol: "DOG", Name: "Other", Supply: 1}, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.ApplyTransaction(again, ctx))
+
+	mint, err := tran.NewMintTransaction("DOG", "alice", "carol", 50, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(mint, ctx))
+	assert.Equal(t, uint64(50), state.Balance("carol", "DOG"))
+
+	forged, err := tran.NewMintTransaction("DOG", "bob", "bob", 50, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.ApplyTransaction(forged, ctx))
+
+	symbols := []string{}
+	for _, tk := range state.Tokens() {
+		symbols = append(symbols, tk.Symbol)
+	}
+	assert.Equal(t, []string{"DOG", "LOLA", "RKY"}, symbols)
+}
+
+// TestSupply verifies minted, burned and circulating amounts are tracked per symbol.
+func TestSupply(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	ctx := Context{Validator: "alice"}
+	state := NewState()
+
+	reward, err := tran.NewCoinbaseTransaction("RKY", "alice", 10, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(reward, ctx))
+
+	burn, err := tran.NewBurnTransaction("RKY", "alice", 4, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(burn, ctx))
+
+	tooMuch, err := tran.NewBurnTransaction("RKY", "alice", 7, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.ApplyTransaction(tooMuch, ctx))
+
+	info, ok := state.Token("RKY")
+	assert.True(t, ok)
+	assert.Equal(t, token.Supply{Minted: 10, Burned: 4, Circulating: 6}, info.Supply)
+	assert.Equal(t, uint64(6), state.Balance("alice", "RKY"))
+}
+
+// TestMintOverflow verifies a mint is rejected when it would overflow the token's supply.
+func TestMintOverflow(t *testing.T) {
+	tm := time.Unix(0, 0).UTC()
+	ctx := Context{}
+	state := NewState()
+
+	issue, err := tran.NewIssueTokenTransaction("alice", tran.IssueToken{Symbol: "DOG", Name: "DogCoin", Supply: math.MaxUint64 - 1, MintAuthority: "alice"}, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(issue, ctx))
+
+	overflow, err := tran.NewMintTransaction("DOG", "alice", "bob", 2, tm)
+	assert.Nil(t, err)
+	assert.NotNil(t, state.ApplyTransaction(overflow, ctx))
+
+	last, err := tran.NewMintTransaction("DOG", "alice", "bob", 1, tm)
+	assert.Nil(t, err)
+	assert.Nil(t, state.ApplyTransaction(last, ctx))
+
+	info, _ := state.Token("DOG")
+	assert.Equal(t, uint64(math.MaxUint64), info.Supply.Minted)
+	assert.Equal(t, uint64(1), state.Balance("bob", "DOG"))
+}
@@ -0,0 +1,106 @@
+package chain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/block"
+)
+
+// ValidateRewards checks the coinbase transactions of a block under params.
+// Each reward symbol may be minted at most once, only to the block's
+// validator, and for no more than the scheduled amount.
+func ValidateRewards(b *block.Block, params Params) error {
+	minted := make(map[string]bool)
+	for _, t := range b.Transactions {
+		if !t.IsCoinbase() {
+			continue
+		}
+
+		scheduled, ok := params.BlockReward(t.Symbol)
+		if !ok {
+			return fmt.Errorf("Block %d: coinbase %s mints unknown reward symbol %s", b.Index, t.ID, t.Symbol)
+		}
+		if minted[t.Symbol] {
+			return fmt.Errorf("Block %d: duplicate coinbase for %s", b.Index, t.Symbol)
+		}
+		if t.Source != b.Validator || t.Destination != b.Validator {
+			return fmt.Errorf("Block %d: coinbase %s is not paid to the block validator", b.Index, t.ID)
+		}
+		if t.Amount > scheduled {
+			return fmt.Errorf("Block %d: coinbase %s exceeds scheduled reward of %d %s", b.Index, t.ID, scheduled, t.Symbol)
+		}
+		minted[t.Symbol] = true
+	}
+
+	return nil
+}
+
+// ValidateBlock runs the validation pipeline for b, the next block after
+// blocks, and applies it to state, which must be the ledger of blocks. The
+// rules are those in force at the block's height: header rules are checked
+// first, then stateless body and transaction rules, and finally each
+// transaction is applied in order. Parameters changed by governance are taken
+// from state.
+func ValidateBlock(blocks []*block.Block, b *block.Block, state *State, now time.Time) error {
+	var parent *block.Block
+	if len(blocks) > 0 {
+		parent = blocks[len(blocks)-1]
+	}
+	params := state.Params(b.Index)
+	if b.Version != params.BlockVersion {
+		return &block.ValidationError{Index: b.Index, Err: block.ErrBadVersion}
+	}
+	if b.Index > 0 && (b.Incrementor == 0 || b.Incrementor%params.Difficulty != 0) {
+		return &block.ValidationError{Index: b.Index, Err: block.ErrBadIncrementor}
+	}
+	if err := b.ValidateHeader(parent, block.MedianTimePast(blocks), now); err != nil {
+		return err
+	}
+	ok, err := b.VerifyBlock()
+	if err != nil {
+		return &block.ValidationError{Index: b.Index, Err: err}
+	}
+	if !ok {
+		return &block.ValidationError{Index: b.Index, Err: block.ErrBadSignature}
+	}
+
+	if err := b.ValidateBody(params.MaxBlockSize); err != nil {
+		return err
+	}
+	for _, t := range b.Transactions {
+		if !params.AllowsType(t.Type) {
+			return fmt.Errorf("Block invalid: %d: transaction %s has inactive type %s", b.Index, t.ID, t.Type)
+		}
+		if !params.AllowsAddresses(t) {
+			return fmt.Errorf("Block invalid: %d: transaction %s uses an inactive address kind", b.Index, t.ID)
+		}
+		if err := t.Validate(b.Time); err != nil {
+			return err
+		}
+		ok, err := t.VerifyTransaction()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("Transaction invalid: %s", t.ID)
+		}
+	}
+	if err := ValidateRewards(b, params); err != nil {
+		return err
+	}
+
+	return state.ApplyBlock(b)
+}
+
+// ValidateBlocks checks every block of a chain received from a peer.
+func ValidateBlocks(blocks []*block.Block, now time.Time) error {
+	state := NewState()
+	for i, b := range blocks {
+		if err := ValidateBlock(blocks[:i], b, state, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
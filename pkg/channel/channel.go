@@ -0,0 +1,165 @@
+// Package channel implements the off-chain side of unidirectional payment
+// channels.
+//
+// A sender funds a channel on chain once, then pays the recipient by handing
+// over balance updates signed with its key, each owing the recipient more
+// than the last. The recipient keeps the latest update and submits it when
+// closing the channel, so any number of payments cost two transactions.
+package channel
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/tran"
+)
+
+// Errors returned when paying through or accepting payments on a channel.
+var (
+	ErrNotOpenTransaction = errors.New("transaction does not open a channel")
+	ErrNotSender          = errors.New("key is not the channel's sender")
+	ErrNoPayment          = errors.New("payment must be positive")
+	ErrExceedsCapacity    = errors.New("payment exceeds the channel's remaining capacity")
+	ErrWrongChannel       = errors.New("update is for another channel")
+	ErrStaleUpdate        = errors.New("update does not pay more than the latest one")
+	ErrBadSignature       = errors.New("update is not signed by the channel's sender")
+)
+
+// Channel is one party's view of a payment channel. Latest is the most
+// recent update the sender has signed, paying nothing until the first
+// payment.
+type Channel struct {
+	ID        string             `json:"id"`
+	Sender    string             `json:"sender"`
+	Recipient string             `json:"recipient"`
+	Symbol    string             `json:"symbol"`
+	Capacity  uint64             `json:"capacity"`
+	Latest    tran.ChannelUpdate `json:"latest"`
+}
+
+// New returns a channel from sender to recipient holding capacity of symbol.
+func New(id, sender, recipient, symbol string, capacity uint64) *Channel {
+	return &Channel{
+		ID:        id,
+		Sender:    sender,
+		Recipient: recipient,
+		Symbol:    symbol,
+		Capacity:  capacity,
+		Latest:    tran.ChannelUpdate{Channel: id},
+	}
+}
+
+// FromTransaction returns the channel opened by t.
+func FromTransaction(t tran.Transaction) (*Channel, error) {
+	if t.Type != tran.TypeOpenChannel {
+		return nil, ErrNotOpenTransaction
+	}
+	return New(t.ID, t.Source, t.Destination, t.Symbol, t.Amount), nil
+}
+
+// Paid returns the total the latest update owes the recipient.
+func (c *Channel) Paid() uint64 {
+	return c.Latest.Paid
+}
+
+// Remaining returns how much more the sender can pay through the channel.
+func (c *Channel) Remaining() uint64 {
+	return c.Capacity - c.Latest.Paid
+}
+
+// Pay signs an update paying the recipient amount more than the latest one.
+// The update is returned for delivery to the recipient.
+func (c *Channel) Pay(key *ecdsa.PrivateKey, amount uint64) (tran.ChannelUpdate, error) {
+	address, err := keys.GetAddress(key)
+	if err != nil {
+		return tran.ChannelUpdate{}, err
+	}
+	if address != c.Sender {
+		return tran.ChannelUpdate{}, ErrNotSender
+	}
+	if amount == 0 {
+		return tran.ChannelUpdate{}, ErrNoPayment
+	}
+	if amount > c.Remaining() {
+		return tran.ChannelUpdate{}, ErrExceedsCapacity
+	}
+
+	update := tran.ChannelUpdate{Channel: c.ID, Paid: c.Latest.Paid + amount}
+	if err := update.Sign(key); err != nil {
+		return tran.ChannelUpdate{}, err
+	}
+	c.Latest = update
+	return update, nil
+}
+
+// Accept verifies an update received from the sender and keeps it as the
+// latest. It returns how much more the update pays than the previous one.
+func (c *Channel) Accept(update tran.ChannelUpdate) (uint64, error) {
+	if update.Channel != c.ID {
+		return 0, ErrWrongChannel
+	}
+	if update.Paid <= c.Latest.Paid {
+		return 0, ErrStaleUpdate
+	}
+	if update.Paid > c.Capacity {
+		return 0, ErrExceedsCapacity
+	}
+	if !update.Verify(c.Sender) {
+		return 0, ErrBadSignature
+	}
+
+	received := update.Paid - c.Latest.Paid
+	c.Latest = update
+	return received, nil
+}
+
+// CloseTransaction returns a transaction by source closing the channel with
+// the latest update.
+func (c *Channel) CloseTransaction(source string, tm time.Time) (tran.Transaction, error) {
+	return tran.NewCloseChannelTransaction(source, c.Latest, tm)
+}
+
+// Save writes the channel to file.
+func (c *Channel) Save(file string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0600)
+}
+
+// Load reads a channel saved with Save.
+func Load(file string) (*Channel, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := &Channel{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetDefaultPath returns the default path to the saved state of a user's
+// channel.
+func GetDefaultPath(id string) (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	dir := fmt.Sprintf("%s/%s", usr.HomeDir, ".lolachain/channels")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s.json", dir, id), nil
+}
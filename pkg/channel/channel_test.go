@@ -0,0 +1,71 @@
+package channel
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/tran"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPayments verifies the recipient accepts increasing updates from the
+// sender and rejects stale, forged or oversized ones.
+func TestPayments(t *testing.T) {
+	senderKey, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	sender, err := keys.GetAddress(senderKey)
+	assert.Nil(t, err)
+	recipientKey, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	recipient, err := keys.GetAddress(recipientKey)
+	assert.Nil(t, err)
+
+	open, err := tran.NewOpenChannelTransaction("RKY", sender, recipient, 10, 5, time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+	out, err := FromTransaction(open)
+	assert.Nil(t, err)
+	in, err := FromTransaction(open)
+	assert.Nil(t, err)
+
+	_, err = out.Pay(recipientKey, 1)
+	assert.Equal(t, ErrNotSender, err)
+	_, err = out.Pay(senderKey, 11)
+	assert.Equal(t, ErrExceedsCapacity, err)
+
+	first, err := out.Pay(senderKey, 3)
+	assert.Nil(t, err)
+	second, err := out.Pay(senderKey, 4)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(7), second.Paid)
+	assert.Equal(t, uint64(3), out.Remaining())
+
+	received, err := in.Accept(first)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), received)
+	received, err = in.Accept(second)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), received)
+
+	_, err = in.Accept(first)
+	assert.Equal(t, ErrStaleUpdate, err)
+
+	forged := tran.ChannelUpdate{Channel: open.ID, Paid: 10}
+	assert.Nil(t, forged.Sign(recipientKey))
+	_, err = in.Accept(forged)
+	assert.Equal(t, ErrBadSignature, err)
+
+	other := second
+	other.Channel = "other"
+	_, err = in.Accept(other)
+	assert.Equal(t, ErrWrongChannel, err)
+
+	file := filepath.Join(t.TempDir(), "channel.json")
+	assert.Nil(t, in.Save(file))
+	loaded, err := Load(file)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(7), loaded.Paid())
+	assert.True(t, loaded.Latest.Verify(sender))
+}
@@ -12,12 +12,19 @@ import (
 
 	"github.com/datravis/lolachain/pkg/block"
 	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/token"
 	"github.com/datravis/lolachain/pkg/tran"
 )
 
-// GetBalances return's a wallet's balances.
-func GetBalances(host string, address string) (map[string]float64, error) {
-	balances := make(map[string]float64)
+// Balances are a wallet's spendable and locked balances in base units.
+type Balances struct {
+	Unlocked map[string]uint64 `json:"unlocked"`
+	Locked   map[string]uint64 `json:"locked"`
+}
+
+// GetBalances return's a wallet's balances in base units.
+func GetBalances(host string, address string) (Balances, error) {
+	var balances Balances
 
 	url := fmt.Sprintf("%s/addresses/%s", host, address)
 	resp, err := http.Get(url)
@@ -55,23 +62,57 @@ func PostPeer(host string, peer string) error {
 	return errors.New(string(body))
 }
 
-// Send submits a new transaction to the lolachain API.
-func Send(host string, keyPair *ecdsa.PrivateKey, dest string, amount float64, symbol string, memo string) error {
+// Send submits a new transaction to the lolachain API. The amount is in base
+// units of the symbol.
+func Send(host string, keyPair *ecdsa.PrivateKey, dest string, amount uint64, symbol string, memo string) error {
+	return send(host, keyPair, dest, amount, symbol, memo, false)
+}
+
+// SendEncrypted is like Send, but encrypts the memo so only the destination
+// can read it.
+func SendEncrypted(host string, keyPair *ecdsa.PrivateKey, dest string, amount uint64, symbol string, memo string) error {
+	return send(host, keyPair, dest, amount, symbol, memo, true)
+}
+
+func send(host string, keyPair *ecdsa.PrivateKey, dest string, amount uint64, symbol string, memo string, encrypt bool) error {
 	address, err := keys.GetAddress(keyPair)
 	if err != nil {
 		return err
 	}
 
+	dest, err = ResolveAddress(host, dest)
+	if err != nil {
+		return err
+	}
+
+	if encrypt {
+		memo, err = tran.EncryptMemo(memo, dest)
+		if err != nil {
+			return err
+		}
+	}
+
 	ts := time.Now().UTC()
 	t, err := tran.NewTransaction(symbol, address, dest, amount, memo, ts)
 	if err != nil {
 		return err
 	}
-	_, _, err = t.SignTransaction(keyPair)
+
+	return SignAndPost(host, keyPair, t)
+}
+
+// SignAndPost signs a transaction and submits it to the lolachain API.
+func SignAndPost(host string, keyPair *ecdsa.PrivateKey, t tran.Transaction) error {
+	_, _, err := t.SignTransaction(keyPair)
 	if err != nil {
 		return err
 	}
 
+	return PostTransaction(host, t)
+}
+
+// PostTransaction submits a signed transaction to the lolachain API.
+func PostTransaction(host string, t tran.Transaction) error {
 	tJSON, err := json.Marshal(t)
 	if err != nil {
 		return err
@@ -135,3 +176,554 @@ func GetPeers(host string) (map[string]bool, error) {
 	return peers, err
 
 }
+
+// GetTokens returns every token on the chain.
+func GetTokens(host string) ([]token.Token, error) {
+	tokens := make([]token.Token, 0)
+
+	url := fmt.Sprintf("%s/tokens", host)
+	resp, err := http.Get(url)
+	if err != nil {
+		return tokens, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return tokens, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &tokens)
+	return tokens, err
+}
+
+// GetToken returns the token with the supplied symbol.
+func GetToken(host string, symbol string) (token.Token, error) {
+	var t token.Token
+
+	url := fmt.Sprintf("%s/tokens/%s", host, symbol)
+	resp, err := http.Get(url)
+	if err != nil {
+		return t, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return t, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return t, fmt.Errorf("unknown symbol %s", symbol)
+	}
+	if resp.StatusCode != 200 {
+		return t, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &t)
+	return t, err
+}
+
+// GetSupply returns the minted, burned and circulating supply of a token.
+func GetSupply(host string, symbol string) (token.Supply, error) {
+	var supply token.Supply
+
+	url := fmt.Sprintf("%s/tokens/%s/supply", host, symbol)
+	resp, err := http.Get(url)
+	if err != nil {
+		return supply, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return supply, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return supply, fmt.Errorf("unknown symbol %s", symbol)
+	}
+
+	err = json.Unmarshal(body, &supply)
+	return supply, err
+}
+
+// Contract is a hash time-locked contract as reported by a validator.
+type Contract struct {
+	ID        string `json:"id"`
+	Sender    string `json:"sender"`
+	Recipient string `json:"recipient"`
+	Symbol    string `json:"symbol"`
+	Amount    uint64 `json:"amount"`
+	Hash      []byte `json:"hash"`
+	Timeout   uint64 `json:"timeout"`
+	State     string `json:"state"`
+	Preimage  []byte `json:"preimage,omitempty"`
+}
+
+// GetContract returns the hash time-locked contract with the supplied id.
+func GetContract(host string, id string) (Contract, error) {
+	var c Contract
+
+	url := fmt.Sprintf("%s/htlcs/%s", host, id)
+	resp, err := http.Get(url)
+	if err != nil {
+		return c, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return c, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return c, fmt.Errorf("unknown contract %s", id)
+	}
+	if resp.StatusCode != 200 {
+		return c, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &c)
+	return c, err
+}
+
+// Channel is a payment channel as reported by a validator.
+type Channel struct {
+	ID        string `json:"id"`
+	Sender    string `json:"sender"`
+	Recipient string `json:"recipient"`
+	Symbol    string `json:"symbol"`
+	Capacity  uint64 `json:"capacity"`
+	Window    uint64 `json:"window"`
+	State     string `json:"state"`
+	Paid      uint64 `json:"paid"`
+	ClosesAt  uint64 `json:"closes_at,omitempty"`
+}
+
+// GetChannel returns the payment channel with the supplied id.
+func GetChannel(host string, id string) (Channel, error) {
+	var c Channel
+
+	url := fmt.Sprintf("%s/channels/%s", host, id)
+	resp, err := http.Get(url)
+	if err != nil {
+		return c, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return c, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return c, fmt.Errorf("unknown channel %s", id)
+	}
+	if resp.StatusCode != 200 {
+		return c, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &c)
+	return c, err
+}
+
+// GetHeight returns the height of the next block on the validator's chain.
+func GetHeight(host string) (uint64, error) {
+	var height uint64
+
+	url := fmt.Sprintf("%s/height", host)
+	resp, err := http.Get(url)
+	if err != nil {
+		return height, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return height, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return height, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &height)
+	return height, err
+}
+
+// NameRecord is a registered name as reported by a validator.
+type NameRecord struct {
+	Name    string `json:"name"`
+	Owner   string `json:"owner"`
+	Target  string `json:"target"`
+	Expires uint64 `json:"expires"`
+}
+
+// GetName returns the record of a registered name.
+func GetName(host string, name string) (NameRecord, error) {
+	var r NameRecord
+
+	url := fmt.Sprintf("%s/names/%s", host, name)
+	resp, err := http.Get(url)
+	if err != nil {
+		return r, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return r, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return r, fmt.Errorf("unknown name %s", name)
+	}
+	if resp.StatusCode != 200 {
+		return r, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &r)
+	return r, err
+}
+
+// ResolveAddress returns dest if it is an address, or the address a
+// registered name resolves to.
+func ResolveAddress(host string, dest string) (string, error) {
+	if !tran.ValidName(dest) {
+		return dest, nil
+	}
+	r, err := GetName(host, dest)
+	if err != nil {
+		return "", err
+	}
+	return r.Target, nil
+}
+
+// AnchorRecord is an anchored digest as reported by a validator.
+type AnchorRecord struct {
+	Digest      string    `json:"digest"`
+	Label       string    `json:"label,omitempty"`
+	Source      string    `json:"source"`
+	Transaction string    `json:"transaction"`
+	Height      uint64    `json:"height"`
+	Time        time.Time `json:"time"`
+}
+
+// GetAnchor returns the earliest anchor of a hex encoded SHA-256 digest.
+func GetAnchor(host string, digest string) (AnchorRecord, error) {
+	var r AnchorRecord
+
+	url := fmt.Sprintf("%s/anchors/%s", host, digest)
+	resp, err := http.Get(url)
+	if err != nil {
+		return r, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return r, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return r, fmt.Errorf("digest %s is not anchored", digest)
+	}
+	if resp.StatusCode != 200 {
+		return r, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &r)
+	return r, err
+}
+
+// NFT is a unique token as reported by a validator. History is only filled
+// in by GetNFT.
+type NFT struct {
+	ID       string        `json:"id"`
+	Owner    string        `json:"owner"`
+	Creator  string        `json:"creator"`
+	Metadata string        `json:"metadata"`
+	History  []NFTTransfer `json:"history,omitempty"`
+}
+
+// NFTTransfer is one change of ownership of a unique token.
+type NFTTransfer struct {
+	From        string    `json:"from,omitempty"`
+	To          string    `json:"to"`
+	Transaction string    `json:"transaction"`
+	Height      uint64    `json:"height"`
+	Time        time.Time `json:"time"`
+}
+
+// GetNFT returns a unique token along with its ownership history.
+func GetNFT(host string, id string) (NFT, error) {
+	var n NFT
+
+	url := fmt.Sprintf("%s/nfts/%s", host, id)
+	resp, err := http.Get(url)
+	if err != nil {
+		return n, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return n, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return n, fmt.Errorf("unknown token %s", id)
+	}
+	if resp.StatusCode != 200 {
+		return n, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &n)
+	return n, err
+}
+
+// GetNFTs returns the unique tokens owned by an address.
+func GetNFTs(host string, address string) ([]NFT, error) {
+	nfts := []NFT{}
+
+	url := fmt.Sprintf("%s/addresses/%s/nfts", host, address)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nfts, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nfts, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &nfts)
+	return nfts, err
+}
+
+// Order is an open limit order as reported by a validator.
+type Order struct {
+	ID        string `json:"id"`
+	Owner     string `json:"owner"`
+	Side      string `json:"side"`
+	Price     uint64 `json:"price"`
+	Quantity  uint64 `json:"quantity"`
+	Remaining uint64 `json:"remaining"`
+}
+
+// OrderBook is the open orders of a market, best price first.
+type OrderBook struct {
+	Bids []Order `json:"bids"`
+	Asks []Order `json:"asks"`
+}
+
+// Trade is a match between a buy and a sell order.
+type Trade struct {
+	Price     uint64    `json:"price"`
+	Quantity  uint64    `json:"quantity"`
+	Buyer     string    `json:"buyer"`
+	Seller    string    `json:"seller"`
+	BuyOrder  string    `json:"buy_order"`
+	SellOrder string    `json:"sell_order"`
+	Height    uint64    `json:"height"`
+	Time      time.Time `json:"time"`
+}
+
+// GetOrderBook returns the open orders of the base/quote market.
+func GetOrderBook(host string, base string, quote string) (OrderBook, error) {
+	var book OrderBook
+
+	url := fmt.Sprintf("%s/markets/%s-%s/orderbook", host, base, quote)
+	resp, err := http.Get(url)
+	if err != nil {
+		return book, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return book, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &book)
+	return book, err
+}
+
+// GetTrades returns the trades of the base/quote market, oldest first.
+func GetTrades(host string, base string, quote string) ([]Trade, error) {
+	trades := []Trade{}
+
+	url := fmt.Sprintf("%s/markets/%s-%s/trades", host, base, quote)
+	resp, err := http.Get(url)
+	if err != nil {
+		return trades, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return trades, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &trades)
+	return trades, err
+}
+
+// Allowance is how much of a symbol a spender may still move out of an
+// owner's balance, in base units.
+type Allowance struct {
+	Spender string `json:"spender"`
+	Symbol  string `json:"symbol"`
+	Limit   uint64 `json:"limit"`
+}
+
+// GetAllowances returns every allowance granted by owner.
+func GetAllowances(host string, owner string) ([]Allowance, error) {
+	allowances := []Allowance{}
+
+	url := fmt.Sprintf("%s/addresses/%s/allowances", host, owner)
+	resp, err := http.Get(url)
+	if err != nil {
+		return allowances, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return allowances, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &allowances)
+	return allowances, err
+}
+
+// AddressTransaction is a transaction sent or received by an address, with
+// the height of the block that included it.
+type AddressTransaction struct {
+	Height      uint64           `json:"height"`
+	Transaction tran.Transaction `json:"transaction"`
+}
+
+// GetTransactions returns the transactions sent or received by address,
+// newest first.
+func GetTransactions(host string, address string) ([]AddressTransaction, error) {
+	history := []AddressTransaction{}
+
+	url := fmt.Sprintf("%s/addresses/%s/transactions", host, address)
+	resp, err := http.Get(url)
+	if err != nil {
+		return history, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return history, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &history)
+	return history, err
+}
+
+// Params are the consensus rules for a block as reported by a validator.
+type Params struct {
+	Height           uint64   `json:"height"`
+	BlockVersion     uint8    `json:"block_version"`
+	Reward           uint64   `json:"reward"`
+	RewardSymbols    []string `json:"reward_symbols"`
+	Difficulty       uint64   `json:"difficulty"`
+	MaxBlockSize     uint64   `json:"max_block_size"`
+	TransactionTypes []string `json:"transaction_types"`
+}
+
+// Proposal is a proposed change to a chain parameter as reported by a
+// validator.
+type Proposal struct {
+	ID         string            `json:"id"`
+	Proposer   string            `json:"proposer"`
+	Param      string            `json:"param"`
+	Value      uint64            `json:"value"`
+	VotingEnds uint64            `json:"voting_ends"`
+	Activates  uint64            `json:"activates"`
+	State      string            `json:"state"`
+	Votes      map[string]string `json:"votes"`
+	Yes        uint64            `json:"yes"`
+	No         uint64            `json:"no"`
+}
+
+// ParamsInfo is the consensus rules for the next block and the approved
+// changes still to come.
+type ParamsInfo struct {
+	Height    uint64     `json:"height"`
+	Params    Params     `json:"params"`
+	Scheduled []Proposal `json:"scheduled"`
+}
+
+// GetParams returns the consensus rules for the next block.
+func GetParams(host string) (ParamsInfo, error) {
+	var info ParamsInfo
+
+	url := fmt.Sprintf("%s/params", host)
+	resp, err := http.Get(url)
+	if err != nil {
+		return info, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &info)
+	return info, err
+}
+
+// GetProposals returns every governance proposal, the most recent first.
+func GetProposals(host string) ([]Proposal, error) {
+	proposals := []Proposal{}
+
+	url := fmt.Sprintf("%s/proposals", host)
+	resp, err := http.Get(url)
+	if err != nil {
+		return proposals, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return proposals, err
+	}
+	defer resp.Body.Close()
+
+	err = json.Unmarshal(body, &proposals)
+	return proposals, err
+}
+
+// GetProposal returns the governance proposal with the supplied id.
+func GetProposal(host string, id string) (Proposal, error) {
+	var p Proposal
+
+	url := fmt.Sprintf("%s/proposals/%s", host, id)
+	resp, err := http.Get(url)
+	if err != nil {
+		return p, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return p, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return p, fmt.Errorf("unknown proposal %s", id)
+	}
+	if resp.StatusCode != 200 {
+		return p, errors.New(string(body))
+	}
+
+	err = json.Unmarshal(body, &p)
+	return p, err
+}
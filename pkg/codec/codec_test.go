@@ -0,0 +1,88 @@
+package codec
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncoder verifies each field type against a hand assembled encoding.
+func TestEncoder(t *testing.T) {
+	e := NewEncoder(Version, KindTransaction)
+	e.Uint8(7)
+	e.Uint64(258)
+	e.String("lola")
+	e.Data(nil)
+	e.Time(time.Unix(1, 5).In(time.FixedZone("X", 3600)))
+	e.BigInt(nil)
+	e.BigInt(big.NewInt(0))
+	e.BigInt(big.NewInt(-513))
+	e.Fixed([]byte{0xaa, 0xbb})
+
+	expected := "01" + "54" +
+		"07" +
+		"0000000000000102" +
+		"00000004" + "6c6f6c61" +
+		"00000000" +
+		"0000000000000001" + "00000005" +
+		"00" +
+		"01" + "00000000" +
+		"02" + "00000002" + "0201" +
+		"aabb"
+	assert.Equal(t, expected, hex.EncodeToString(e.Bytes()))
+}
+
+// TestEncoderTimePrecision verifies sub-second precision and zones don't collapse.
+func TestEncoderTimePrecision(t *testing.T) {
+	tm := time.Unix(1521000000, 123456789)
+
+	a := NewEncoder(Version, KindBlockHeader)
+	a.Time(tm)
+	b := NewEncoder(Version, KindBlockHeader)
+	b.Time(tm.UTC())
+	c := NewEncoder(Version, KindBlockHeader)
+	c.Time(tm.Add(time.Nanosecond))
+
+	assert.Equal(t, a.Bytes(), b.Bytes())
+	assert.NotEqual(t, a.Bytes(), c.Bytes())
+}
+
+// TestDecoder verifies every field written by an Encoder reads back unchanged.
+func TestDecoder(t *testing.T) {
+	tm := time.Unix(1521000000, 123456789).UTC()
+
+	var e Encoder
+	e.Uint8(7)
+	e.Uint64(258)
+	e.String("lola")
+	e.Data([]byte{1, 2})
+	e.Time(tm)
+	e.BigInt(nil)
+	e.BigInt(big.NewInt(-513))
+	e.Fixed([]byte{0xaa, 0xbb})
+
+	d := NewDecoder(e.Bytes())
+	assert.Equal(t, uint8(7), d.Uint8())
+	assert.Equal(t, uint64(258), d.Uint64())
+	assert.Equal(t, "lola", d.String())
+	assert.Equal(t, []byte{1, 2}, d.Data())
+	assert.Equal(t, tm, d.Time())
+	assert.Nil(t, d.BigInt())
+	assert.Equal(t, big.NewInt(-513), d.BigInt())
+	assert.Equal(t, []byte{0xaa, 0xbb}, d.Fixed(2))
+	assert.Nil(t, d.Done())
+}
+
+// TestDecoderErrors verifies truncated and oversized encodings are rejected.
+func TestDecoderErrors(t *testing.T) {
+	d := NewDecoder([]byte{0, 0, 0, 9, 1})
+	assert.Nil(t, d.Data())
+	assert.Equal(t, ErrTruncated, d.Done())
+
+	d = NewDecoder([]byte{1, 2})
+	d.Uint8()
+	assert.Equal(t, ErrTrailingData, d.Done())
+}
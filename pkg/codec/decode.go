@@ -0,0 +1,128 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// ErrTruncated is returned when an encoding ends before a field is complete.
+var ErrTruncated = errors.New("codec: truncated encoding")
+
+// ErrTrailingData is returned by Done when bytes are left after the last
+// field.
+var ErrTrailingData = errors.New("codec: trailing data")
+
+// Decoder reads fields from an encoding without a version and kind header,
+// such as a transaction payload. The first error is sticky: once a read fails
+// every later read returns a zero value and Err reports the failure.
+type Decoder struct {
+	buf []byte
+	err error
+}
+
+// NewDecoder returns a decoder reading from b.
+func NewDecoder(b []byte) *Decoder {
+	return &Decoder{buf: b}
+}
+
+// Err returns the first error encountered while decoding.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Done returns the first decoding error, or ErrTrailingData if any bytes were
+// left unread.
+func (d *Decoder) Done() error {
+	if d.err == nil && len(d.buf) > 0 {
+		d.err = ErrTrailingData
+	}
+	return d.err
+}
+
+func (d *Decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.buf) < n {
+		d.err = ErrTruncated
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+// Uint8 reads a single byte.
+func (d *Decoder) Uint8() uint8 {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// Uint64 reads a big-endian uint64.
+func (d *Decoder) Uint64() uint64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// Data reads a length prefixed byte slice.
+func (d *Decoder) Data() []byte {
+	b := d.next(4)
+	if b == nil {
+		return nil
+	}
+	n := binary.BigEndian.Uint32(b)
+	if uint64(n) > uint64(len(d.buf)) {
+		d.err = ErrTruncated
+		return nil
+	}
+	v := make([]byte, n)
+	copy(v, d.next(int(n)))
+	return v
+}
+
+// String reads a length prefixed string.
+func (d *Decoder) String() string {
+	return string(d.Data())
+}
+
+// Time reads Unix seconds and nanoseconds, returning a UTC time.
+func (d *Decoder) Time() time.Time {
+	sec := d.Uint64()
+	b := d.next(4)
+	if b == nil {
+		return time.Time{}
+	}
+	return time.Unix(int64(sec), int64(binary.BigEndian.Uint32(b))).UTC()
+}
+
+// BigInt reads a sign and magnitude, returning nil for an encoded nil.
+func (d *Decoder) BigInt() *big.Int {
+	sign := d.Uint8()
+	if d.err != nil || sign == 0 {
+		return nil
+	}
+	n := new(big.Int).SetBytes(d.Data())
+	if sign == 2 {
+		n.Neg(n)
+	}
+	return n
+}
+
+// Fixed reads n bytes without a length prefix.
+func (d *Decoder) Fixed(n int) []byte {
+	b := d.next(n)
+	if b == nil {
+		return nil
+	}
+	v := make([]byte, n)
+	copy(v, b)
+	return v
+}
@@ -0,0 +1,82 @@
+package keys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// EncryptionOverhead is how many bytes Encrypt adds to a message for a P-224
+// key: the compressed ephemeral key and the GCM tag.
+const EncryptionOverhead = 1 + 28 + 16
+
+// ErrDecrypt is returned when a message was not encrypted to the key trying
+// to decrypt it, or has been altered.
+var ErrDecrypt = errors.New("message can't be decrypted with this key")
+
+// Encrypt encrypts plaintext so only the holder of pub's private key can read
+// it. Each message uses a fresh ephemeral key, whose ECDH secret with pub
+// keys AES-256-GCM. Since no key is used twice the nonce is fixed at zero.
+//
+// The result is the compressed ephemeral public key followed by the sealed
+// plaintext, EncryptionOverhead bytes longer than the plaintext for the
+// P-224 keys used for addresses.
+func Encrypt(pub *ecdsa.PublicKey, plaintext []byte) ([]byte, error) {
+	ephemeral, err := ecdsa.GenerateKey(pub.Curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	point := elliptic.MarshalCompressed(pub.Curve, ephemeral.PublicKey.X, ephemeral.PublicKey.Y)
+
+	aead, err := eciesCipher(pub.Curve, pub.X, pub.Y, ephemeral.D, point)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(point, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts a message sealed by Encrypt to key's public key.
+func Decrypt(key *ecdsa.PrivateKey, message []byte) ([]byte, error) {
+	size := 1 + (key.Curve.Params().BitSize+7)/8
+	if len(message) < size {
+		return nil, ErrDecrypt
+	}
+	point := message[:size]
+	x, y := elliptic.UnmarshalCompressed(key.Curve, point)
+	if x == nil {
+		return nil, ErrDecrypt
+	}
+
+	aead, err := eciesCipher(key.Curve, x, y, key.D, point)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	plaintext, err := aead.Open(nil, nonce, message[size:], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// eciesCipher returns the AEAD keyed by the ECDH secret of the point (x, y)
+// and the scalar d, bound to the ephemeral key's encoding.
+func eciesCipher(curve elliptic.Curve, x, y, d *big.Int, ephemeral []byte) (cipher.AEAD, error) {
+	sx, _ := curve.ScalarMult(x, y, d.Bytes())
+	secret := sx.FillBytes(make([]byte, (curve.Params().BitSize+7)/8))
+
+	h := sha256.New()
+	h.Write(ephemeral)
+	h.Write(secret)
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,34 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncrypt verifies only the holder of the key a message was encrypted to
+// can decrypt it, and that altered messages are rejected.
+func TestEncrypt(t *testing.T) {
+	key, err := GenerateKeyPair()
+	assert.Nil(t, err)
+	other, err := GenerateKeyPair()
+	assert.Nil(t, err)
+
+	sealed, err := Encrypt(&key.PublicKey, []byte("invoice 1042"))
+	assert.Nil(t, err)
+	assert.Equal(t, len("invoice 1042")+EncryptionOverhead, len(sealed))
+
+	plaintext, err := Decrypt(key, sealed)
+	assert.Nil(t, err)
+	assert.Equal(t, "invoice 1042", string(plaintext))
+
+	_, err = Decrypt(other, sealed)
+	assert.Equal(t, ErrDecrypt, err)
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = Decrypt(key, sealed)
+	assert.Equal(t, ErrDecrypt, err)
+
+	_, err = Decrypt(key, []byte("short"))
+	assert.Equal(t, ErrDecrypt, err)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"os/user"
 
@@ -43,7 +44,11 @@ func DecodeAddress(address string) (*ecdsa.PublicKey, error) {
 		return nil, err
 	}
 
-	return pub.(*ecdsa.PublicKey), nil
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("address is not an ecdsa public key")
+	}
+	return ecdsaPub, nil
 }
 
 // WriteKeys writes a keypair to a pem file.
@@ -153,3 +158,18 @@ func LoadOrGenerateKeys(filename string) (*ecdsa.PrivateKey, error) {
 
 	return LoadKeys(filename)
 }
+
+// IsLowS reports whether s is in the lower half of the curve order. Only one
+// of s and N-s is accepted so signatures can't be altered by a third party.
+func IsLowS(pub *ecdsa.PublicKey, s *big.Int) bool {
+	halfOrder := new(big.Int).Rsh(pub.Curve.Params().N, 1)
+	return s.Sign() > 0 && s.Cmp(halfOrder) <= 0
+}
+
+// NormalizeS returns the low-S form of a signature's s value.
+func NormalizeS(pub *ecdsa.PublicKey, s *big.Int) *big.Int {
+	if IsLowS(pub, s) {
+		return s
+	}
+	return new(big.Int).Sub(pub.Curve.Params().N, s)
+}
@@ -1,6 +1,7 @@
 package keys
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -40,3 +41,18 @@ func TestDeodeAddress(t *testing.T) {
 	assert.NotNil(t, pubK)
 	assert.Equal(t, k.PublicKey, *pubK)
 }
+
+// TestNormalizeS confirms high-S values are mapped to their low-S counterpart.
+func TestNormalizeS(t *testing.T) {
+	k, err := GenerateKeyPair()
+	assert.Nil(t, err)
+
+	n := k.Curve.Params().N
+	low := big.NewInt(12345)
+	high := new(big.Int).Sub(n, low)
+
+	assert.True(t, IsLowS(&k.PublicKey, low))
+	assert.False(t, IsLowS(&k.PublicKey, high))
+	assert.Equal(t, low, NormalizeS(&k.PublicKey, high))
+	assert.Equal(t, low, NormalizeS(&k.PublicKey, low))
+}
@@ -0,0 +1,173 @@
+package keys
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+	"sort"
+
+	"github.com/datravis/lolachain/pkg/codec"
+
+	"github.com/lytics/base62"
+)
+
+// MaxMultisigKeys is the most public keys a multisig address may list.
+const MaxMultisigKeys = 15
+
+// Errors returned for malformed multisig addresses.
+var (
+	ErrNotMultisig      = errors.New("address is not a multisig address")
+	ErrBadThreshold     = errors.New("threshold must be between 1 and the number of keys")
+	ErrTooManyKeys      = errors.New("too many keys in multisig address")
+	ErrDuplicateKey     = errors.New("duplicate key in multisig address")
+	ErrUnsortedMultisig = errors.New("multisig keys are not in canonical order")
+)
+
+// Multisig is an M-of-N policy: spending from its address needs signatures
+// from Threshold of Keys.
+type Multisig struct {
+	Threshold int
+	Keys      []*ecdsa.PublicKey
+}
+
+// NewMultisig returns a policy needing threshold signatures from pubs. The
+// keys are sorted, so the same set and threshold always give the same
+// address.
+func NewMultisig(threshold int, pubs []*ecdsa.PublicKey) (*Multisig, error) {
+	if len(pubs) > MaxMultisigKeys {
+		return nil, ErrTooManyKeys
+	}
+	if threshold < 1 || threshold > len(pubs) {
+		return nil, ErrBadThreshold
+	}
+
+	encoded := make([][]byte, len(pubs))
+	for i, pub := range pubs {
+		b, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = b
+	}
+	order := make([]int, len(pubs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return bytes.Compare(encoded[order[i]], encoded[order[j]]) < 0
+	})
+
+	m := &Multisig{Threshold: threshold}
+	for i, o := range order {
+		if i > 0 && bytes.Equal(encoded[o], encoded[order[i-1]]) {
+			return nil, ErrDuplicateKey
+		}
+		m.Keys = append(m.Keys, pubs[o])
+	}
+	return m, nil
+}
+
+// Address returns the base62 encoded address of the policy. Like a single
+// key address it carries the public keys themselves, so it can be verified
+// without any other data.
+func (m *Multisig) Address() (string, error) {
+	e := codec.NewEncoder(codec.Version, codec.KindMultisig)
+	e.Uint8(uint8(m.Threshold))
+	e.Uint8(uint8(len(m.Keys)))
+	for _, pub := range m.Keys {
+		b, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return "", err
+		}
+		e.Data(b)
+	}
+
+	return base62.StdEncoding.EncodeToString(e.Bytes()), nil
+}
+
+// IndexOf returns the position of pub in the policy's keys, or -1 if it is
+// not one of them.
+func (m *Multisig) IndexOf(pub *ecdsa.PublicKey) int {
+	for i, k := range m.Keys {
+		if k.X.Cmp(pub.X) == 0 && k.Y.Cmp(pub.Y) == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsMultisigAddress reports whether address is a multisig address. It does
+// not check that the address is well formed.
+func IsMultisigAddress(address string) bool {
+	b, err := base62.StdEncoding.DecodeString(address)
+	if err != nil {
+		return false
+	}
+	return len(b) >= 2 && b[0] == codec.Version && b[1] == codec.KindMultisig
+}
+
+// DecodeMultisigAddress decodes a multisig address into its policy. Addresses
+// whose keys are not in canonical order are rejected, so every policy has a
+// single address.
+func DecodeMultisigAddress(address string) (*Multisig, error) {
+	b, err := base62.StdEncoding.DecodeString(address)
+	if err != nil {
+		return nil, err
+	}
+
+	d := codec.NewDecoder(b)
+	if d.Uint8() != codec.Version || d.Uint8() != codec.KindMultisig {
+		return nil, ErrNotMultisig
+	}
+	threshold := int(d.Uint8())
+	n := int(d.Uint8())
+	if n > MaxMultisigKeys {
+		return nil, ErrTooManyKeys
+	}
+	var prev []byte
+	pubs := make([]*ecdsa.PublicKey, 0, n)
+	for i := 0; i < n; i++ {
+		der := d.Data()
+		if d.Err() != nil {
+			break
+		}
+		if prev != nil && bytes.Compare(prev, der) >= 0 {
+			return nil, ErrUnsortedMultisig
+		}
+		prev = der
+
+		pub, err := x509.ParsePKIXPublicKey(der)
+		if err != nil {
+			return nil, err
+		}
+		ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, ErrNotMultisig
+		}
+		pubs = append(pubs, ecdsaPub)
+	}
+	if err := d.Done(); err != nil {
+		return nil, err
+	}
+	if threshold < 1 || threshold > n {
+		return nil, ErrBadThreshold
+	}
+
+	return &Multisig{Threshold: threshold, Keys: pubs}, nil
+}
+
+// ValidateAddress checks that address is a single key, multisig or script
+// address.
+func ValidateAddress(address string) error {
+	if IsMultisigAddress(address) {
+		_, err := DecodeMultisigAddress(address)
+		return err
+	}
+	if IsScriptAddress(address) {
+		_, err := DecodeScriptAddress(address)
+		return err
+	}
+	_, err := DecodeAddress(address)
+	return err
+}
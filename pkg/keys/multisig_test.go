@@ -0,0 +1,76 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generatePubs(t *testing.T, n int) []*ecdsa.PublicKey {
+	pubs := make([]*ecdsa.PublicKey, n)
+	for i := range pubs {
+		k, err := GenerateKeyPair()
+		assert.Nil(t, err)
+		pubs[i] = &k.PublicKey
+	}
+	return pubs
+}
+
+// TestMultisigAddress confirms a multisig address round trips and doesn't
+// depend on the order its keys were given in.
+func TestMultisigAddress(t *testing.T) {
+	pubs := generatePubs(t, 3)
+
+	m, err := NewMultisig(2, pubs)
+	assert.Nil(t, err)
+	address, err := m.Address()
+	assert.Nil(t, err)
+	assert.True(t, IsMultisigAddress(address))
+	assert.Nil(t, ValidateAddress(address))
+
+	reversed, err := NewMultisig(2, []*ecdsa.PublicKey{pubs[2], pubs[1], pubs[0]})
+	assert.Nil(t, err)
+	reversedAddress, err := reversed.Address()
+	assert.Nil(t, err)
+	assert.Equal(t, address, reversedAddress)
+
+	decoded, err := DecodeMultisigAddress(address)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, decoded.Threshold)
+	assert.Equal(t, 3, len(decoded.Keys))
+	for _, pub := range pubs {
+		assert.True(t, decoded.IndexOf(pub) >= 0)
+	}
+
+	_, err = DecodeAddress(address)
+	assert.NotNil(t, err)
+}
+
+// TestNewMultisigErrors checks the rules for a multisig policy.
+func TestNewMultisigErrors(t *testing.T) {
+	pubs := generatePubs(t, 2)
+
+	_, err := NewMultisig(0, pubs)
+	assert.Equal(t, ErrBadThreshold, err)
+	_, err = NewMultisig(3, pubs)
+	assert.Equal(t, ErrBadThreshold, err)
+	_, err = NewMultisig(1, []*ecdsa.PublicKey{pubs[0], pubs[0]})
+	assert.Equal(t, ErrDuplicateKey, err)
+	_, err = NewMultisig(1, generatePubs(t, MaxMultisigKeys+1))
+	assert.Equal(t, ErrTooManyKeys, err)
+}
+
+// TestSingleKeyIsNotMultisig checks that ordinary addresses aren't mistaken
+// for multisig ones.
+func TestSingleKeyIsNotMultisig(t *testing.T) {
+	k, err := GenerateKeyPair()
+	assert.Nil(t, err)
+	address, err := GetAddress(k)
+	assert.Nil(t, err)
+
+	assert.False(t, IsMultisigAddress(address))
+	assert.Nil(t, ValidateAddress(address))
+	_, err = DecodeMultisigAddress(address)
+	assert.Equal(t, ErrNotMultisig, err)
+}
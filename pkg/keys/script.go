@@ -0,0 +1,51 @@
+package keys
+
+import (
+	"errors"
+
+	"github.com/datravis/lolachain/pkg/codec"
+
+	"github.com/lytics/base62"
+)
+
+// ErrNotScript is returned when decoding an address that is not a script
+// address.
+var ErrNotScript = errors.New("address is not a script address")
+
+// ScriptAddress returns the address of funds locked by a locking script.
+// Like a single key address it carries the script itself. The script is not
+// checked; see package script.
+func ScriptAddress(lock []byte) string {
+	e := codec.NewEncoder(codec.Version, codec.KindScript)
+	e.Data(lock)
+	return base62.StdEncoding.EncodeToString(e.Bytes())
+}
+
+// IsScriptAddress reports whether address is a script address. It does not
+// check that the address is well formed.
+func IsScriptAddress(address string) bool {
+	b, err := base62.StdEncoding.DecodeString(address)
+	if err != nil {
+		return false
+	}
+	return len(b) >= 2 && b[0] == codec.Version && b[1] == codec.KindScript
+}
+
+// DecodeScriptAddress returns the locking script carried by a script
+// address.
+func DecodeScriptAddress(address string) ([]byte, error) {
+	b, err := base62.StdEncoding.DecodeString(address)
+	if err != nil {
+		return nil, err
+	}
+
+	d := codec.NewDecoder(b)
+	if d.Uint8() != codec.Version || d.Uint8() != codec.KindScript {
+		return nil, ErrNotScript
+	}
+	lock := d.Data()
+	if err := d.Done(); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
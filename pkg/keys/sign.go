@@ -0,0 +1,25 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"math/big"
+)
+
+// Sign signs digest with key, returning a low-S signature.
+func Sign(key *ecdsa.PrivateKey, digest []byte) (*big.Int, *big.Int, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, NormalizeS(&key.PublicKey, s), nil
+}
+
+// Verify reports whether r and s are a valid low-S signature of digest by
+// pub.
+func Verify(pub *ecdsa.PublicKey, digest []byte, r, s *big.Int) bool {
+	if r == nil || s == nil || !IsLowS(pub, s) {
+		return false
+	}
+	return ecdsa.Verify(pub, digest, r, s)
+}
@@ -0,0 +1,29 @@
+package keys
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignVerify verifies signatures are low-S and that high-S or foreign
+// signatures are rejected.
+func TestSignVerify(t *testing.T) {
+	key, err := GenerateKeyPair()
+	assert.Nil(t, err)
+	other, err := GenerateKeyPair()
+	assert.Nil(t, err)
+	digest := sha256.Sum256([]byte("payment"))
+
+	r, s, err := Sign(key, digest[:])
+	assert.Nil(t, err)
+	assert.True(t, IsLowS(&key.PublicKey, s))
+	assert.True(t, Verify(&key.PublicKey, digest[:], r, s))
+	assert.False(t, Verify(&other.PublicKey, digest[:], r, s))
+
+	highS := new(big.Int).Sub(key.Curve.Params().N, s)
+	assert.False(t, Verify(&key.PublicKey, digest[:], r, highS))
+	assert.False(t, Verify(&key.PublicKey, digest[:], nil, nil))
+}
@@ -0,0 +1,215 @@
+package keys
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"math/big"
+
+	"github.com/datravis/lolachain/pkg/codec"
+
+	"github.com/lytics/base62"
+)
+
+// Errors returned for malformed stealth addresses and ephemeral keys.
+var (
+	ErrNotStealth       = errors.New("address is not a stealth address")
+	ErrInvalidEphemeral = errors.New("ephemeral key is not a public key on the address's curve")
+)
+
+// StealthKeys are the two key pairs behind a stealth address. The scan key
+// finds payments to the address, so it can be handed to a watch-only wallet;
+// the spend key is needed as well to spend them.
+//
+// A sender pays a stealth address by picking an ephemeral key r and paying
+// the one-time key Spend + H(r*Scan)*G, publishing r*G with the payment. Only
+// the holder of the scan key can compute the same shared secret from r*G,
+// so payments to one stealth address can't be linked to each other.
+type StealthKeys struct {
+	Scan  *ecdsa.PrivateKey
+	Spend *ecdsa.PrivateKey
+}
+
+// GenerateStealthKeys generates new scan and spend key pairs.
+func GenerateStealthKeys() (*StealthKeys, error) {
+	scan, err := GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	spend, err := GenerateKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	return &StealthKeys{Scan: scan, Spend: spend}, nil
+}
+
+// LoadOrGenerateStealthKeys loads the scan and spend keys at the provided
+// paths, generating either if needed.
+func LoadOrGenerateStealthKeys(scanFile, spendFile string) (*StealthKeys, error) {
+	scan, err := LoadOrGenerateKeys(scanFile)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := LoadOrGenerateKeys(spendFile)
+	if err != nil {
+		return nil, err
+	}
+	return &StealthKeys{Scan: scan, Spend: spend}, nil
+}
+
+// Address returns the base62 encoded stealth address publishing both public
+// keys. It is not a destination itself; senders derive one from it with
+// NewStealthDestination.
+func (k *StealthKeys) Address() (string, error) {
+	e := codec.NewEncoder(codec.Version, codec.KindStealth)
+	for _, pub := range []*ecdsa.PublicKey{&k.Scan.PublicKey, &k.Spend.PublicKey} {
+		b, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return "", err
+		}
+		e.Data(b)
+	}
+	return base62.StdEncoding.EncodeToString(e.Bytes()), nil
+}
+
+// IsStealthAddress reports whether address is a stealth address. It does not
+// check that the address is well formed.
+func IsStealthAddress(address string) bool {
+	b, err := base62.StdEncoding.DecodeString(address)
+	if err != nil {
+		return false
+	}
+	return len(b) >= 2 && b[0] == codec.Version && b[1] == codec.KindStealth
+}
+
+// DecodeStealthAddress returns the scan and spend public keys of a stealth
+// address.
+func DecodeStealthAddress(address string) (*ecdsa.PublicKey, *ecdsa.PublicKey, error) {
+	b, err := base62.StdEncoding.DecodeString(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d := codec.NewDecoder(b)
+	if d.Uint8() != codec.Version || d.Uint8() != codec.KindStealth {
+		return nil, nil, ErrNotStealth
+	}
+	scanBytes := d.Data()
+	spendBytes := d.Data()
+	if err := d.Done(); err != nil {
+		return nil, nil, err
+	}
+
+	scan, err := DecodeEphemeralKey(scanBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	spend, err := DecodeEphemeralKey(spendBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scan.Curve != spend.Curve {
+		return nil, nil, ErrNotStealth
+	}
+	return scan, spend, nil
+}
+
+// DecodeEphemeralKey parses a DER encoded ecdsa public key, such as the
+// ephemeral key published with a stealth payment.
+func DecodeEphemeralKey(b []byte) (*ecdsa.PublicKey, error) {
+	pub, err := x509.ParsePKIXPublicKey(b)
+	if err != nil {
+		return nil, ErrInvalidEphemeral
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrInvalidEphemeral
+	}
+	return ecdsaPub, nil
+}
+
+// NewStealthDestination derives a one-time destination for a payment to a
+// stealth address. The ephemeral key must be published with the payment so
+// the recipient can find it.
+func NewStealthDestination(address string) (string, []byte, error) {
+	scan, spend, err := DecodeStealthAddress(address)
+	if err != nil {
+		return "", nil, err
+	}
+	r, err := ecdsa.GenerateKey(scan.Curve, rand.Reader)
+	if err != nil {
+		return "", nil, err
+	}
+	ephemeral, err := x509.MarshalPKIXPublicKey(&r.PublicKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	h := stealthTweak(scan, r.D, ephemeral)
+	curve := spend.Curve
+	hx, hy := curve.ScalarBaseMult(h.Bytes())
+	x, y := curve.Add(spend.X, spend.Y, hx, hy)
+
+	dest, err := encodePublicKey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+	if err != nil {
+		return "", nil, err
+	}
+	return dest, ephemeral, nil
+}
+
+// Find reports whether a payment to dest published with ephemeral is for
+// these keys. If it is, the private key of dest is returned; otherwise the
+// key is nil.
+func (k *StealthKeys) Find(dest string, ephemeral []byte) (*ecdsa.PrivateKey, error) {
+	pub, err := DecodeEphemeralKey(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	curve := k.Spend.Curve
+	if pub.Curve != curve || !curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, ErrInvalidEphemeral
+	}
+
+	h := stealthTweak(pub, k.Scan.D, ephemeral)
+	d := new(big.Int).Add(k.Spend.D, h)
+	d.Mod(d, curve.Params().N)
+	key := &ecdsa.PrivateKey{D: d}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d.Bytes())
+
+	address, err := GetAddress(key)
+	if err != nil {
+		return nil, err
+	}
+	if address != dest {
+		return nil, nil
+	}
+	return key, nil
+}
+
+// stealthTweak hashes the ECDH secret of a private scalar and the other
+// party's public key, together with the ephemeral key, to a scalar.
+func stealthTweak(pub *ecdsa.PublicKey, d *big.Int, ephemeral []byte) *big.Int {
+	curve := pub.Curve
+	x, _ := curve.ScalarMult(pub.X, pub.Y, d.Bytes())
+
+	size := (curve.Params().BitSize + 7) / 8
+	e := codec.NewEncoder(codec.Version, codec.KindStealth)
+	e.Fixed(x.FillBytes(make([]byte, size)))
+	e.Data(ephemeral)
+	sum := sha256.Sum256(e.Bytes())
+
+	h := new(big.Int).SetBytes(sum[:])
+	return h.Mod(h, curve.Params().N)
+}
+
+// encodePublicKey returns the address of a public key.
+func encodePublicKey(pub *ecdsa.PublicKey) (string, error) {
+	b, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return base62.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,48 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStealthPayment verifies the recipient, and only the recipient, finds a
+// payment to its stealth address and can sign for the one-time destination.
+func TestStealthPayment(t *testing.T) {
+	recipient, err := GenerateStealthKeys()
+	assert.Nil(t, err)
+	other, err := GenerateStealthKeys()
+	assert.Nil(t, err)
+
+	address, err := recipient.Address()
+	assert.Nil(t, err)
+	assert.True(t, IsStealthAddress(address))
+	assert.NotNil(t, ValidateAddress(address))
+
+	dest, ephemeral, err := NewStealthDestination(address)
+	assert.Nil(t, err)
+	assert.Nil(t, ValidateAddress(dest))
+
+	again, _, err := NewStealthDestination(address)
+	assert.Nil(t, err)
+	assert.NotEqual(t, dest, again)
+
+	key, err := recipient.Find(dest, ephemeral)
+	assert.Nil(t, err)
+	assert.NotNil(t, key)
+	keyAddress, err := GetAddress(key)
+	assert.Nil(t, err)
+	assert.Equal(t, dest, keyAddress)
+
+	key, err = other.Find(dest, ephemeral)
+	assert.Nil(t, err)
+	assert.Nil(t, key)
+
+	_, err = recipient.Find(dest, []byte("junk"))
+	assert.Equal(t, ErrInvalidEphemeral, err)
+
+	pub, err := encodePublicKey(&recipient.Scan.PublicKey)
+	assert.Nil(t, err)
+	_, _, err = NewStealthDestination(pub)
+	assert.NotNil(t, err)
+}
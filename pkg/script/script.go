@@ -0,0 +1,223 @@
+// Package script implements a small, deterministic stack machine for
+// spending conditions.
+//
+// Funds sent to a script address are locked by the script the address
+// carries. Spending them needs an unlocking script, the transaction's
+// witness, that only pushes data. The unlocking script runs first, then the
+// locking script on the same stack, and the spend is authorized if neither
+// fails and the top of the stack is true.
+//
+// Stack items are byte strings. Numbers are unsigned, big-endian and at most
+// 8 bytes, and an item is true if any of its bytes is non-zero. Every
+// opcode costs gas, and a script that runs out fails, so evaluation always
+// terminates quickly.
+package script
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Opcodes.
+const (
+	// OpFalse pushes an empty item.
+	OpFalse byte = 0x00
+	// OpTrue pushes 0x01.
+	OpTrue byte = 0x01
+	// OpPush pushes the data that follows it, prefixed with its uint16
+	// big-endian length.
+	OpPush byte = 0x02
+
+	// OpDup duplicates the top item.
+	OpDup byte = 0x10
+	// OpDrop removes the top item.
+	OpDrop byte = 0x11
+	// OpSwap swaps the top two items.
+	OpSwap byte = 0x12
+	// OpOver copies the second item to the top.
+	OpOver byte = 0x13
+
+	// OpEqual pushes whether the top two items are equal.
+	OpEqual byte = 0x20
+	// OpEqualVerify is OpEqual followed by OpVerify.
+	OpEqualVerify byte = 0x21
+	// OpVerify fails unless the top item is true, which it removes.
+	OpVerify byte = 0x22
+	// OpNot replaces the top item with whether it is false.
+	OpNot byte = 0x23
+
+	// OpIf runs the following branch if the top item is true, otherwise the
+	// branch after OpElse, if any. Branches end with OpEndIf.
+	OpIf    byte = 0x28
+	OpElse  byte = 0x29
+	OpEndIf byte = 0x2a
+
+	// OpSHA256 replaces the top item with its SHA-256 digest.
+	OpSHA256 byte = 0x30
+
+	// OpCheckSig pops a public key and a signature and pushes whether the
+	// signature is a valid low-S signature of the transaction by the key.
+	OpCheckSig byte = 0x40
+	// OpCheckSigVerify is OpCheckSig followed by OpVerify.
+	OpCheckSigVerify byte = 0x41
+	// OpCheckMultisig pops a key count n, n public keys, a threshold m and m
+	// signatures, and pushes whether each signature matches one of the keys
+	// in the same order as the keys.
+	OpCheckMultisig byte = 0x42
+
+	// OpCheckHeight fails unless the block height is at least the top item,
+	// which it leaves on the stack.
+	OpCheckHeight byte = 0x50
+	// OpCheckTime fails unless the block time, in Unix seconds, is at least
+	// the top item, which it leaves on the stack.
+	OpCheckTime byte = 0x51
+)
+
+// Limits on scripts and their evaluation.
+const (
+	MaxScriptSize = 10000
+	MaxItemSize   = 520
+	MaxStackSize  = 1000
+	MaxGas        = 10000
+)
+
+// Errors returned by Run.
+var (
+	ErrScriptTooLarge = errors.New("script: too large")
+	ErrPushOnly       = errors.New("script: unlocking script may only push data")
+	ErrBadOpcode      = errors.New("script: unknown opcode")
+	ErrTruncated      = errors.New("script: truncated push")
+	ErrItemTooLarge   = errors.New("script: stack item too large")
+	ErrStackUnderflow = errors.New("script: stack underflow")
+	ErrStackOverflow  = errors.New("script: stack overflow")
+	ErrUnbalancedIf   = errors.New("script: unbalanced conditional")
+	ErrNumberTooLarge = errors.New("script: number too large")
+	ErrVerifyFailed   = errors.New("script: verify failed")
+	ErrLocked         = errors.New("script: spending condition not yet reached")
+	ErrOutOfGas       = errors.New("script: out of gas")
+	ErrFalse          = errors.New("script: evaluated to false")
+)
+
+// Builder assembles a script.
+type Builder struct {
+	buf []byte
+}
+
+// Op appends opcodes.
+func (b *Builder) Op(ops ...byte) *Builder {
+	b.buf = append(b.buf, ops...)
+	return b
+}
+
+// Push appends an OpPush of data.
+func (b *Builder) Push(data []byte) *Builder {
+	var n [2]byte
+	binary.BigEndian.PutUint16(n[:], uint16(len(data)))
+	b.buf = append(b.buf, OpPush)
+	b.buf = append(b.buf, n[:]...)
+	b.buf = append(b.buf, data...)
+	return b
+}
+
+// PushInt appends an OpPush of n in its minimal encoding.
+func (b *Builder) PushInt(n uint64) *Builder {
+	return b.Push(EncodeInt(n))
+}
+
+// Script returns the assembled script.
+func (b *Builder) Script() []byte {
+	return append([]byte{}, b.buf...)
+}
+
+// EncodeInt returns the minimal big-endian encoding of n. Zero is the empty
+// item.
+func EncodeInt(n uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], n)
+	i := 0
+	for i < len(b) && b[i] == 0 {
+		i++
+	}
+	return append([]byte{}, b[i:]...)
+}
+
+// DecodeInt reads a number from a stack item.
+func DecodeInt(item []byte) (uint64, error) {
+	if len(item) > 8 {
+		return 0, ErrNumberTooLarge
+	}
+	var n uint64
+	for _, c := range item {
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+// instruction is a decoded opcode and, for pushes, its data.
+type instruction struct {
+	op   byte
+	data []byte
+}
+
+// parse decodes a script into instructions.
+func parse(s []byte) ([]instruction, error) {
+	if len(s) > MaxScriptSize {
+		return nil, ErrScriptTooLarge
+	}
+	var ins []instruction
+	for i := 0; i < len(s); {
+		op := s[i]
+		i++
+		if op != OpPush {
+			if !known(op) {
+				return nil, fmt.Errorf("%w 0x%02x", ErrBadOpcode, op)
+			}
+			ins = append(ins, instruction{op: op})
+			continue
+		}
+		if i+2 > len(s) {
+			return nil, ErrTruncated
+		}
+		n := int(binary.BigEndian.Uint16(s[i:]))
+		i += 2
+		if i+n > len(s) {
+			return nil, ErrTruncated
+		}
+		if n > MaxItemSize {
+			return nil, ErrItemTooLarge
+		}
+		ins = append(ins, instruction{op: op, data: s[i : i+n]})
+		i += n
+	}
+	return ins, nil
+}
+
+func known(op byte) bool {
+	switch op {
+	case OpFalse, OpTrue, OpPush,
+		OpDup, OpDrop, OpSwap, OpOver,
+		OpEqual, OpEqualVerify, OpVerify, OpNot,
+		OpIf, OpElse, OpEndIf,
+		OpSHA256,
+		OpCheckSig, OpCheckSigVerify, OpCheckMultisig,
+		OpCheckHeight, OpCheckTime:
+		return true
+	}
+	return false
+}
+
+// IsPushOnly reports whether s is a well formed script that only pushes
+// data, as unlocking scripts must.
+func IsPushOnly(s []byte) bool {
+	ins, err := parse(s)
+	if err != nil {
+		return false
+	}
+	for _, in := range ins {
+		if in.op != OpFalse && in.op != OpTrue && in.op != OpPush {
+			return false
+		}
+	}
+	return true
+}
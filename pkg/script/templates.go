@@ -0,0 +1,68 @@
+package script
+
+import (
+	"crypto/ecdsa"
+)
+
+// Standard locking scripts. Each documents the unlocking script that spends
+// it.
+
+// PayToKey locks funds to pub. It is unlocked by a push of a signature by
+// pub.
+func PayToKey(pub *ecdsa.PublicKey) ([]byte, error) {
+	der, err := PublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return new(Builder).Push(der).Op(OpCheckSig).Script(), nil
+}
+
+// Multisig locks funds to threshold of pubs. It is unlocked by pushes of
+// threshold signatures, in the same order as their keys in pubs.
+func Multisig(threshold int, pubs []*ecdsa.PublicKey) ([]byte, error) {
+	b := new(Builder).PushInt(uint64(threshold))
+	for _, pub := range pubs {
+		der, err := PublicKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		b.Push(der)
+	}
+	return b.PushInt(uint64(len(pubs))).Op(OpCheckMultisig).Script(), nil
+}
+
+// AfterHeight wraps a locking script so it can't be unlocked before height.
+// It is unlocked as the wrapped script is.
+func AfterHeight(height uint64, inner []byte) []byte {
+	return new(Builder).PushInt(height).Op(OpCheckHeight, OpDrop).Op(inner...).Script()
+}
+
+// AfterTime wraps a locking script so it can't be unlocked before a block
+// timestamped at or after unix seconds. It is unlocked as the wrapped script
+// is.
+func AfterTime(unix uint64, inner []byte) []byte {
+	return new(Builder).PushInt(unix).Op(OpCheckTime, OpDrop).Op(inner...).Script()
+}
+
+// HashLock locks funds to recipient with the preimage of hash, or to
+// refund once the chain reaches timeout. The recipient unlocks it with pushes
+// of a signature, the preimage and OpTrue; the refund with a signature and
+// OpFalse.
+func HashLock(hash []byte, recipient, refund *ecdsa.PublicKey, timeout uint64) ([]byte, error) {
+	recipientDER, err := PublicKey(recipient)
+	if err != nil {
+		return nil, err
+	}
+	refundDER, err := PublicKey(refund)
+	if err != nil {
+		return nil, err
+	}
+	return new(Builder).
+		Op(OpIf).
+		Op(OpSHA256).Push(hash).Op(OpEqualVerify).Push(recipientDER).
+		Op(OpElse).
+		PushInt(timeout).Op(OpCheckHeight, OpDrop).Push(refundDER).
+		Op(OpEndIf).
+		Op(OpCheckSig).
+		Script(), nil
+}
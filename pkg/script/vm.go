@@ -0,0 +1,412 @@
+package script
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+)
+
+// Gas costs of opcodes. Every instruction, including pushes and those in a
+// branch that isn't taken, costs at least GasBase.
+const (
+	GasBase     = 1
+	GasPerWord  = 1
+	GasSHA256   = 10
+	GasCheckSig = 100
+)
+
+// MaxMultisigKeys is the most keys OpCheckMultisig accepts.
+const MaxMultisigKeys = 20
+
+// Env is what a script can observe about the spending transaction and the
+// block it is checked in.
+type Env struct {
+	// Digest is the SHA-256 digest of the transaction's signing bytes, the
+	// message OpCheckSig verifies signatures against.
+	Digest []byte
+	Height uint64
+	Time   time.Time
+}
+
+// Sign returns a low-S signature of digest by key, encoded as OpCheckSig
+// expects.
+func Sign(key *ecdsa.PrivateKey, digest []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, err
+	}
+	var e codec.Encoder
+	e.BigInt(r)
+	e.BigInt(keys.NormalizeS(&key.PublicKey, s))
+	return e.Bytes(), nil
+}
+
+// PublicKey returns the encoding of pub that OpCheckSig expects.
+func PublicKey(pub *ecdsa.PublicKey) ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(pub)
+}
+
+// Run evaluates the unlocking script followed by the locking script in env.
+// It returns nil if the spend is authorized.
+func Run(unlock, lock []byte, env Env) error {
+	if !IsPushOnly(unlock) {
+		return ErrPushOnly
+	}
+	unlockIns, err := parse(unlock)
+	if err != nil {
+		return err
+	}
+	lockIns, err := parse(lock)
+	if err != nil {
+		return err
+	}
+
+	m := &machine{env: env, gas: MaxGas}
+	if err := m.run(unlockIns); err != nil {
+		return err
+	}
+	if err := m.run(lockIns); err != nil {
+		return err
+	}
+	if len(m.stack) == 0 || !truthy(m.stack[len(m.stack)-1]) {
+		return ErrFalse
+	}
+	return nil
+}
+
+type machine struct {
+	env   Env
+	stack [][]byte
+	gas   int
+}
+
+func (m *machine) charge(gas int) error {
+	m.gas -= gas
+	if m.gas < 0 {
+		return ErrOutOfGas
+	}
+	return nil
+}
+
+func (m *machine) push(item []byte) error {
+	if len(item) > MaxItemSize {
+		return ErrItemTooLarge
+	}
+	if len(m.stack) >= MaxStackSize {
+		return ErrStackOverflow
+	}
+	m.stack = append(m.stack, item)
+	return nil
+}
+
+func (m *machine) pop() ([]byte, error) {
+	if len(m.stack) == 0 {
+		return nil, ErrStackUnderflow
+	}
+	item := m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+	return item, nil
+}
+
+func (m *machine) peek(depth int) ([]byte, error) {
+	if len(m.stack) <= depth {
+		return nil, ErrStackUnderflow
+	}
+	return m.stack[len(m.stack)-1-depth], nil
+}
+
+func (m *machine) popInt() (uint64, error) {
+	item, err := m.pop()
+	if err != nil {
+		return 0, err
+	}
+	return DecodeInt(item)
+}
+
+// popItems pops n items and returns them in the order they were pushed.
+func (m *machine) popItems(n int) ([][]byte, error) {
+	if len(m.stack) < n {
+		return nil, ErrStackUnderflow
+	}
+	items := append([][]byte{}, m.stack[len(m.stack)-n:]...)
+	m.stack = m.stack[:len(m.stack)-n]
+	return items, nil
+}
+
+func (m *machine) run(ins []instruction) error {
+	// conds holds whether each enclosing conditional branch is being taken,
+	// and falses how many of them are not, so checking whether to execute
+	// an instruction doesn't depend on the nesting depth.
+	var conds []bool
+	falses := 0
+	executing := func() bool {
+		return falses == 0
+	}
+
+	for _, in := range ins {
+		if err := m.charge(GasBase); err != nil {
+			return err
+		}
+
+		switch in.op {
+		case OpIf:
+			taken := false
+			if executing() {
+				v, err := m.pop()
+				if err != nil {
+					return err
+				}
+				taken = truthy(v)
+			}
+			conds = append(conds, taken)
+			if !taken {
+				falses++
+			}
+			continue
+		case OpElse:
+			if len(conds) == 0 {
+				return ErrUnbalancedIf
+			}
+			top := len(conds) - 1
+			if conds[top] {
+				falses++
+			} else {
+				falses--
+			}
+			conds[top] = !conds[top]
+			continue
+		case OpEndIf:
+			if len(conds) == 0 {
+				return ErrUnbalancedIf
+			}
+			if !conds[len(conds)-1] {
+				falses--
+			}
+			conds = conds[:len(conds)-1]
+			continue
+		}
+		if !executing() {
+			continue
+		}
+		if err := m.step(in); err != nil {
+			return err
+		}
+	}
+
+	if len(conds) != 0 {
+		return ErrUnbalancedIf
+	}
+	return nil
+}
+
+func (m *machine) step(in instruction) error {
+	switch in.op {
+	case OpFalse:
+		return m.push([]byte{})
+	case OpTrue:
+		return m.push([]byte{1})
+	case OpPush:
+		if err := m.charge(GasPerWord * (len(in.data) + 31) / 32); err != nil {
+			return err
+		}
+		return m.push(in.data)
+
+	case OpDup:
+		v, err := m.peek(0)
+		if err != nil {
+			return err
+		}
+		return m.push(v)
+	case OpDrop:
+		_, err := m.pop()
+		return err
+	case OpSwap:
+		if len(m.stack) < 2 {
+			return ErrStackUnderflow
+		}
+		n := len(m.stack)
+		m.stack[n-1], m.stack[n-2] = m.stack[n-2], m.stack[n-1]
+		return nil
+	case OpOver:
+		v, err := m.peek(1)
+		if err != nil {
+			return err
+		}
+		return m.push(v)
+
+	case OpEqual, OpEqualVerify:
+		items, err := m.popItems(2)
+		if err != nil {
+			return err
+		}
+		equal := bytes.Equal(items[0], items[1])
+		if in.op == OpEqualVerify {
+			if !equal {
+				return ErrVerifyFailed
+			}
+			return nil
+		}
+		return m.push(boolItem(equal))
+	case OpVerify:
+		v, err := m.pop()
+		if err != nil {
+			return err
+		}
+		if !truthy(v) {
+			return ErrVerifyFailed
+		}
+		return nil
+	case OpNot:
+		v, err := m.pop()
+		if err != nil {
+			return err
+		}
+		return m.push(boolItem(!truthy(v)))
+
+	case OpSHA256:
+		if err := m.charge(GasSHA256); err != nil {
+			return err
+		}
+		v, err := m.pop()
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(v)
+		return m.push(sum[:])
+
+	case OpCheckSig, OpCheckSigVerify:
+		if err := m.charge(GasCheckSig); err != nil {
+			return err
+		}
+		items, err := m.popItems(2)
+		if err != nil {
+			return err
+		}
+		ok := m.checkSig(items[0], items[1])
+		if in.op == OpCheckSigVerify {
+			if !ok {
+				return ErrVerifyFailed
+			}
+			return nil
+		}
+		return m.push(boolItem(ok))
+	case OpCheckMultisig:
+		return m.checkMultisig()
+
+	case OpCheckHeight:
+		v, err := m.peek(0)
+		if err != nil {
+			return err
+		}
+		height, err := DecodeInt(v)
+		if err != nil {
+			return err
+		}
+		if m.env.Height < height {
+			return ErrLocked
+		}
+		return nil
+	case OpCheckTime:
+		v, err := m.peek(0)
+		if err != nil {
+			return err
+		}
+		unix, err := DecodeInt(v)
+		if err != nil {
+			return err
+		}
+		if m.env.Time.Unix() < 0 || uint64(m.env.Time.Unix()) < unix {
+			return ErrLocked
+		}
+		return nil
+	}
+
+	return ErrBadOpcode
+}
+
+// checkMultisig implements OpCheckMultisig.
+func (m *machine) checkMultisig() error {
+	n, err := m.popInt()
+	if err != nil {
+		return err
+	}
+	if n > MaxMultisigKeys {
+		return ErrNumberTooLarge
+	}
+	if err := m.charge(GasCheckSig * int(n)); err != nil {
+		return err
+	}
+	pubs, err := m.popItems(int(n))
+	if err != nil {
+		return err
+	}
+	threshold, err := m.popInt()
+	if err != nil {
+		return err
+	}
+	if threshold > n {
+		return ErrNumberTooLarge
+	}
+	sigs, err := m.popItems(int(threshold))
+	if err != nil {
+		return err
+	}
+
+	k := 0
+	for _, sig := range sigs {
+		for k < len(pubs) && !m.checkSig(sig, pubs[k]) {
+			k++
+		}
+		if k == len(pubs) {
+			return m.push(boolItem(false))
+		}
+		k++
+	}
+	return m.push(boolItem(true))
+}
+
+// checkSig reports whether sig is a valid low-S signature of the
+// transaction by pub. Malformed signatures and keys are simply invalid.
+func (m *machine) checkSig(sig, pub []byte) bool {
+	key, err := x509.ParsePKIXPublicKey(pub)
+	if err != nil {
+		return false
+	}
+	ecdsaPub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return false
+	}
+
+	d := codec.NewDecoder(sig)
+	r := d.BigInt()
+	s := d.BigInt()
+	if d.Done() != nil || r == nil || s == nil || r.Sign() <= 0 {
+		return false
+	}
+	if !keys.IsLowS(ecdsaPub, s) {
+		return false
+	}
+	return ecdsa.Verify(ecdsaPub, m.env.Digest, r, s)
+}
+
+func truthy(item []byte) bool {
+	for _, c := range item {
+		if c != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func boolItem(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+	return []byte{}
+}
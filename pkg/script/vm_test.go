@@ -0,0 +1,162 @@
+package script
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generateKey(t *testing.T) *ecdsa.PrivateKey {
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	return k
+}
+
+func testEnv() Env {
+	sum := sha256.Sum256([]byte("transaction"))
+	return Env{Digest: sum[:], Height: 10, Time: time.Unix(1000, 0).UTC()}
+}
+
+// TestPayToKey verifies a single key script accepts only its key's
+// signature.
+func TestPayToKey(t *testing.T) {
+	env := testEnv()
+	owner := generateKey(t)
+	other := generateKey(t)
+
+	lock, err := PayToKey(&owner.PublicKey)
+	assert.Nil(t, err)
+
+	sig, err := Sign(owner, env.Digest)
+	assert.Nil(t, err)
+	assert.Nil(t, Run(new(Builder).Push(sig).Script(), lock, env))
+
+	forged, err := Sign(other, env.Digest)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrFalse, Run(new(Builder).Push(forged).Script(), lock, env))
+
+	assert.Equal(t, ErrPushOnly, Run(new(Builder).Op(OpTrue, OpVerify).Script(), lock, env))
+}
+
+// TestMultisigScript verifies a 2-of-3 script needs two signatures in key
+// order.
+func TestMultisigScript(t *testing.T) {
+	env := testEnv()
+	ks := []*ecdsa.PrivateKey{generateKey(t), generateKey(t), generateKey(t)}
+	lock, err := Multisig(2, []*ecdsa.PublicKey{&ks[0].PublicKey, &ks[1].PublicKey, &ks[2].PublicKey})
+	assert.Nil(t, err)
+
+	sig0, err := Sign(ks[0], env.Digest)
+	assert.Nil(t, err)
+	sig2, err := Sign(ks[2], env.Digest)
+	assert.Nil(t, err)
+
+	assert.Nil(t, Run(new(Builder).Push(sig0).Push(sig2).Script(), lock, env))
+	assert.Equal(t, ErrFalse, Run(new(Builder).Push(sig2).Push(sig0).Script(), lock, env))
+	assert.Equal(t, ErrFalse, Run(new(Builder).Push(sig0).Push(sig0).Script(), lock, env))
+	assert.Equal(t, ErrStackUnderflow, Run(new(Builder).Push(sig0).Script(), lock, env))
+}
+
+// TestTimeLocks verifies height and time locks hold until reached.
+func TestTimeLocks(t *testing.T) {
+	env := testEnv()
+	owner := generateKey(t)
+	inner, err := PayToKey(&owner.PublicKey)
+	assert.Nil(t, err)
+	sig, err := Sign(owner, env.Digest)
+	assert.Nil(t, err)
+	unlock := new(Builder).Push(sig).Script()
+
+	assert.Nil(t, Run(unlock, AfterHeight(10, inner), env))
+	assert.Equal(t, ErrLocked, Run(unlock, AfterHeight(11, inner), env))
+	assert.Nil(t, Run(unlock, AfterTime(1000, inner), env))
+	assert.Equal(t, ErrLocked, Run(unlock, AfterTime(1001, inner), env))
+}
+
+// TestHashLock verifies the recipient can spend with the preimage and the
+// sender only after the timeout.
+func TestHashLock(t *testing.T) {
+	env := testEnv()
+	recipient := generateKey(t)
+	refund := generateKey(t)
+	secret := []byte("secret")
+	hash := sha256.Sum256(secret)
+
+	lock, err := HashLock(hash[:], &recipient.PublicKey, &refund.PublicKey, 20)
+	assert.Nil(t, err)
+
+	recipientSig, err := Sign(recipient, env.Digest)
+	assert.Nil(t, err)
+	assert.Nil(t, Run(new(Builder).Push(recipientSig).Push(secret).Op(OpTrue).Script(), lock, env))
+	assert.Equal(t, ErrVerifyFailed, Run(new(Builder).Push(recipientSig).Push([]byte("guess")).Op(OpTrue).Script(), lock, env))
+
+	refundSig, err := Sign(refund, env.Digest)
+	assert.Nil(t, err)
+	unlock := new(Builder).Push(refundSig).Op(OpFalse).Script()
+	assert.Equal(t, ErrLocked, Run(unlock, lock, env))
+	env.Height = 20
+	assert.Nil(t, Run(unlock, lock, env))
+}
+
+// TestGas verifies evaluation stops when a script runs out of gas.
+func TestGas(t *testing.T) {
+	b := new(Builder).Op(OpTrue)
+	for i := 0; i < MaxGas; i++ {
+		b.Op(OpDup, OpDrop)
+	}
+	assert.Equal(t, ErrScriptTooLarge, Run(nil, b.Script(), testEnv()))
+
+	b = new(Builder).Op(OpTrue)
+	for i := 0; i < MaxGas/GasSHA256; i++ {
+		b.Op(OpSHA256)
+	}
+	assert.Equal(t, ErrOutOfGas, Run(nil, b.Script(), testEnv()))
+}
+
+// TestMalformedScripts verifies scripts that can't be parsed or don't balance
+// are rejected.
+func TestMalformedScripts(t *testing.T) {
+	env := testEnv()
+	_, err := parse([]byte{0xff})
+	assert.ErrorIs(t, err, ErrBadOpcode)
+	assert.Equal(t, ErrTruncated, Run(nil, []byte{OpPush, 0, 5, 1}, env))
+	assert.Equal(t, ErrUnbalancedIf, Run(nil, []byte{OpTrue, OpIf, OpTrue}, env))
+	assert.Equal(t, ErrUnbalancedIf, Run(nil, []byte{OpTrue, OpEndIf}, env))
+	assert.Equal(t, ErrStackUnderflow, Run(nil, []byte{OpDrop}, env))
+	assert.Equal(t, ErrFalse, Run(nil, []byte{OpFalse}, env))
+	assert.Nil(t, Run(nil, []byte{OpFalse, OpIf, OpFalse, OpElse, OpTrue, OpEndIf}, env))
+}
+
+// TestNestedConditionals verifies branches nested inside a skipped branch
+// stay skipped whichever way they go.
+func TestNestedConditionals(t *testing.T) {
+	env := testEnv()
+	skipped := []byte{OpFalse, OpIf, OpFalse, OpIf, OpElse, OpFalse, OpEndIf, OpFalse, OpElse, OpTrue, OpEndIf}
+	assert.Nil(t, Run(nil, skipped, env))
+
+	taken := []byte{OpTrue, OpIf, OpFalse, OpIf, OpFalse, OpElse, OpTrue, OpEndIf, OpElse, OpFalse, OpEndIf}
+	assert.Nil(t, Run(nil, taken, env))
+
+	b := new(Builder).Op(OpFalse)
+	for i := 0; i < MaxGas/GasBase-2; i++ {
+		b.Op(OpIf)
+	}
+	assert.Equal(t, ErrUnbalancedIf, Run(nil, b.Script(), env))
+}
+
+// TestEncodeInt verifies numbers use their minimal encoding.
+func TestEncodeInt(t *testing.T) {
+	assert.Equal(t, []byte{}, EncodeInt(0))
+	assert.Equal(t, []byte{1, 0}, EncodeInt(256))
+
+	n, err := DecodeInt([]byte{1, 0})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(256), n)
+	_, err = DecodeInt(make([]byte, 9))
+	assert.Equal(t, ErrNumberTooLarge, err)
+}
@@ -0,0 +1,81 @@
+package token
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseAmount parses a non-negative decimal string such as "12.5" into base
+// units of a token with the supplied number of decimals. It is exact: amounts
+// with more fractional digits than decimals, or that overflow, are rejected.
+func ParseAmount(s string, decimals uint8) (uint64, error) {
+	if decimals > MaxDecimals {
+		return 0, errors.New("too many decimals")
+	}
+
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" {
+		return 0, errors.New("empty amount")
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, errors.New("amount must be a non-negative decimal number")
+	}
+	if len(frac) > int(decimals) {
+		return 0, errors.New("too many decimal places")
+	}
+
+	frac += strings.Repeat("0", int(decimals)-len(frac))
+	digits := strings.TrimLeft(whole+frac, "0")
+	if digits == "" {
+		return 0, nil
+	}
+
+	amount, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, errors.New("amount out of range")
+	}
+	return amount, nil
+}
+
+// FormatAmount formats base units of a token with the supplied number of
+// decimals, omitting trailing zeros.
+func FormatAmount(amount uint64, decimals uint8) string {
+	s := strconv.FormatUint(amount, 10)
+	if decimals == 0 {
+		return s
+	}
+
+	if len(s) <= int(decimals) {
+		s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n uint8) uint64 {
+	if n > 19 {
+		return math.MaxUint64
+	}
+	p := uint64(1)
+	for i := uint8(0); i < n; i++ {
+		p *= 10
+	}
+	return p
+}
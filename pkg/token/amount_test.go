@@ -0,0 +1,55 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseAmount verifies decimal strings are converted to base units exactly.
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		in       string
+		decimals uint8
+		out      uint64
+	}{
+		{"1", 8, 100000000},
+		{"1.5", 8, 150000000},
+		{"0.00000001", 8, 1},
+		{".25", 2, 25},
+		{"7.", 2, 700},
+		{"0", 8, 0},
+		{"42", 0, 42},
+		{"184467440737.09551615", 8, 18446744073709551615},
+	}
+
+	for _, c := range cases {
+		amount, err := ParseAmount(c.in, c.decimals)
+		assert.Nil(t, err, c.in)
+		assert.Equal(t, c.out, amount, c.in)
+	}
+}
+
+// TestParseAmountInvalid verifies malformed and inexact amounts are rejected.
+func TestParseAmountInvalid(t *testing.T) {
+	for _, in := range []string{"", ".", "-1", "+1", "1e8", "1.2.3", "abc", "0.000000001", "184467440737.09551616", "NaN"} {
+		_, err := ParseAmount(in, 8)
+		assert.NotNil(t, err, in)
+	}
+}
+
+// TestFormatAmount verifies base units are formatted without losing precision.
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "1", FormatAmount(100000000, 8))
+	assert.Equal(t, "1.5", FormatAmount(150000000, 8))
+	assert.Equal(t, "0.00000001", FormatAmount(1, 8))
+	assert.Equal(t, "0", FormatAmount(0, 8))
+	assert.Equal(t, "42", FormatAmount(42, 0))
+	assert.Equal(t, "184467440737.09551615", FormatAmount(18446744073709551615, 8))
+
+	for _, s := range []string{"1", "0.1", "123.456", "0"} {
+		amount, err := ParseAmount(s, 8)
+		assert.Nil(t, err)
+		assert.Equal(t, s, FormatAmount(amount, 8))
+	}
+}
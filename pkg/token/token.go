@@ -0,0 +1,86 @@
+package token
+
+import "fmt"
+
+// Token describes a fungible token on the blockchain. Amounts of a token are
+// stored as integer base units, one whole token being 10^Decimals units.
+type Token struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// Supply accounts for every unit of a token created or destroyed, in base
+// units.
+type Supply struct {
+	Minted      uint64 `json:"minted"`
+	Burned      uint64 `json:"burned"`
+	Circulating uint64 `json:"circulating"`
+}
+
+const (
+	// MaxDecimals is the largest number of decimals a token may use.
+	MaxDecimals = 18
+
+	// MaxSymbolLength is the longest symbol a token may use.
+	MaxSymbolLength = 12
+
+	// MaxNameLength is the longest name, in bytes, a token may use.
+	MaxNameLength = 64
+)
+
+var builtins = []Token{
+	{Symbol: "RKY", Name: "RockyCoin", Decimals: 8},
+	{Symbol: "LOLA", Name: "LolaCoin", Decimals: 8},
+}
+
+// Builtins returns the tokens that exist from the genesis block.
+func Builtins() []Token {
+	tokens := make([]Token, len(builtins))
+	copy(tokens, builtins)
+	return tokens
+}
+
+// ValidSymbol reports whether s is a well formed token symbol: an upper case
+// letter followed by upper case letters or digits, at most MaxSymbolLength
+// long.
+func ValidSymbol(s string) bool {
+	if len(s) == 0 || len(s) > MaxSymbolLength || s[0] < 'A' || s[0] > 'Z' {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup returns the builtin token with the supplied symbol.
+func Lookup(symbol string) (Token, bool) {
+	for _, t := range builtins {
+		if t.Symbol == symbol {
+			return t, true
+		}
+	}
+	return Token{}, false
+}
+
+// Units returns the number of base units in whole tokens.
+func (t Token) Units(whole uint64) uint64 {
+	return whole * pow10(t.Decimals)
+}
+
+// Parse parses a decimal amount of the token into base units.
+func (t Token) Parse(s string) (uint64, error) {
+	amount, err := ParseAmount(s, t.Decimals)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s amount: %s", t.Symbol, err)
+	}
+	return amount, nil
+}
+
+// Format formats an amount of base units as a decimal string.
+func (t Token) Format(amount uint64) string {
+	return FormatAmount(amount, t.Decimals)
+}
@@ -0,0 +1,38 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLookup verifies the builtin tokens can be found by symbol.
+func TestLookup(t *testing.T) {
+	rky, ok := Lookup("RKY")
+	assert.True(t, ok)
+	assert.Equal(t, "RockyCoin", rky.Name)
+
+	_, ok = Lookup("NOPE")
+	assert.False(t, ok)
+}
+
+// TestUnits verifies whole tokens are converted using the token's decimals.
+func TestUnits(t *testing.T) {
+	tk := Token{Symbol: "TEST", Decimals: 3}
+	assert.Equal(t, uint64(5000), tk.Units(5))
+
+	amount, err := tk.Parse("1.25")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1250), amount)
+	assert.Equal(t, "1.25", tk.Format(amount))
+}
+
+// TestValidSymbol verifies symbol formatting rules.
+func TestValidSymbol(t *testing.T) {
+	for _, s := range []string{"RKY", "LOLA", "A", "DOG2"} {
+		assert.True(t, ValidSymbol(s), s)
+	}
+	for _, s := range []string{"", "rky", "2DOG", "LO LA", "LOLA-1", "ABCDEFGHIJKLM"} {
+		assert.False(t, ValidSymbol(s), s)
+	}
+}
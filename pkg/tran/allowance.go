@@ -0,0 +1,116 @@
+package tran
+
+import (
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/token"
+)
+
+// Allowance transaction types.
+const (
+	// TypeApprove sets how much of a symbol a spender may move out of the
+	// sender's balance. A zero limit revokes the allowance.
+	TypeApprove = "approve"
+	// TypeSpendAllowance moves Amount of Symbol from the owner in its payload
+	// to Destination, drawing down the sender's allowance.
+	TypeSpendAllowance = "spend-allowance"
+)
+
+// Errors returned when validating allowance payloads.
+var (
+	ErrInvalidSpender      = errors.New("malformed spender address")
+	ErrInvalidOwnerAddress = errors.New("malformed owner address")
+)
+
+func init() {
+	RegisterType(TypeApprove, TypeRules{NewPayload: func() Payload { return &Approve{} }})
+	RegisterType(TypeSpendAllowance, TypeRules{
+		NewPayload:  func() Payload { return &SpendAllowance{} },
+		Amount:      true,
+		Destination: true,
+	})
+}
+
+// Approve is the payload of a TypeApprove transaction.
+type Approve struct {
+	Spender string
+	Symbol  string
+	// Limit is the most the spender may move, in base units. It replaces
+	// any earlier allowance rather than adding to it.
+	Limit uint64
+}
+
+// SpendAllowance is the payload of a TypeSpendAllowance transaction.
+type SpendAllowance struct {
+	Owner string
+}
+
+// NewApproveTransaction returns a transaction allowing spender to move up to
+// limit of symbol from source. A zero limit revokes the allowance.
+func NewApproveTransaction(source, spender, symbol string, limit uint64, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeApprove, source, &Approve{Spender: spender, Symbol: symbol, Limit: limit}, "", tm)
+}
+
+// NewSpendAllowanceTransaction returns a transaction by spender moving amount
+// of symbol from owner to dest.
+func NewSpendAllowanceTransaction(symbol, spender, owner, dest string, amount uint64, memo string, tm time.Time) (Transaction, error) {
+	t := Transaction{
+		Type:        TypeSpendAllowance,
+		Symbol:      symbol,
+		Source:      spender,
+		Destination: dest,
+		Amount:      amount,
+		Memo:        memo,
+		Time:        tm,
+	}
+	t.SetPayload(&SpendAllowance{Owner: owner})
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// Encode implements Payload.
+func (p *Approve) Encode(e *codec.Encoder) {
+	e.String(p.Spender)
+	e.String(p.Symbol)
+	e.Uint64(p.Limit)
+}
+
+// Decode implements Payload.
+func (p *Approve) Decode(d *codec.Decoder) {
+	p.Spender = d.String()
+	p.Symbol = d.String()
+	p.Limit = d.Uint64()
+}
+
+// Validate implements Payload.
+func (p *Approve) Validate() error {
+	if err := keys.ValidateAddress(p.Spender); err != nil {
+		return ErrInvalidSpender
+	}
+	if !token.ValidSymbol(p.Symbol) {
+		return ErrInvalidSymbol
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *SpendAllowance) Encode(e *codec.Encoder) {
+	e.String(p.Owner)
+}
+
+// Decode implements Payload.
+func (p *SpendAllowance) Decode(d *codec.Decoder) {
+	p.Owner = d.String()
+}
+
+// Validate implements Payload.
+func (p *SpendAllowance) Validate() error {
+	if err := keys.ValidateAddress(p.Owner); err != nil {
+		return ErrInvalidOwnerAddress
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package tran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllowanceValidate verifies the allowance payload rules.
+func TestAllowanceValidate(t *testing.T) {
+	assert.Equal(t, ErrInvalidSpender, (&Approve{Spender: "not_an_address", Symbol: "RKY", Limit: 5}).Validate())
+	assert.Equal(t, ErrInvalidOwnerAddress, (&SpendAllowance{Owner: "not_an_address"}).Validate())
+}
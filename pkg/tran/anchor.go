@@ -0,0 +1,61 @@
+package tran
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// TypeAnchor records the SHA-256 digest of a document on chain, proving the
+// document existed by the time of the block that includes it.
+const TypeAnchor = "anchor"
+
+// MaxLabelLength is the longest label, in bytes, an anchor may carry.
+const MaxLabelLength = 128
+
+// Errors returned when validating an Anchor payload.
+var (
+	ErrInvalidDigest = errors.New("anchor must be a SHA-256 digest")
+	ErrLabelTooLong  = errors.New("label too long")
+)
+
+func init() {
+	RegisterType(TypeAnchor, TypeRules{NewPayload: func() Payload { return &Anchor{} }})
+}
+
+// Anchor is the payload of a TypeAnchor transaction.
+type Anchor struct {
+	Digest []byte
+	Label  string
+}
+
+// NewAnchorTransaction returns a transaction anchoring digest with an
+// optional label.
+func NewAnchorTransaction(source string, digest []byte, label string, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeAnchor, source, &Anchor{Digest: digest, Label: label}, "", tm)
+}
+
+// Encode implements Payload.
+func (p *Anchor) Encode(e *codec.Encoder) {
+	e.Data(p.Digest)
+	e.String(p.Label)
+}
+
+// Decode implements Payload.
+func (p *Anchor) Decode(d *codec.Decoder) {
+	p.Digest = d.Data()
+	p.Label = d.String()
+}
+
+// Validate implements Payload.
+func (p *Anchor) Validate() error {
+	if len(p.Digest) != sha256.Size {
+		return ErrInvalidDigest
+	}
+	if len(p.Label) > MaxLabelLength {
+		return ErrLabelTooLong
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package tran
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAnchorValidate verifies the anchor payload rules.
+func TestAnchorValidate(t *testing.T) {
+	digest := sha256.Sum256([]byte("contract.pdf"))
+
+	assert.Nil(t, (&Anchor{Digest: digest[:], Label: "contract"}).Validate())
+	assert.Equal(t, ErrInvalidDigest, (&Anchor{Digest: digest[:16]}).Validate())
+	assert.Equal(t, ErrLabelTooLong, (&Anchor{Digest: digest[:], Label: strings.Repeat("a", MaxLabelLength+1)}).Validate())
+}
@@ -0,0 +1,111 @@
+package tran
+
+import (
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/token"
+)
+
+// TypeBatch pays every output in its payload from the sender under a single
+// signature. Either every output is paid or none is.
+const TypeBatch = "batch"
+
+// MaxBatchOutputs is the most outputs a batch transfer may pay.
+const MaxBatchOutputs = 1000
+
+// Errors returned when validating a Batch payload.
+var (
+	ErrEmptyBatch    = errors.New("batch needs at least one output")
+	ErrBatchTooLarge = errors.New("too many outputs in batch")
+)
+
+func init() {
+	RegisterType(TypeBatch, TypeRules{NewPayload: func() Payload { return &Batch{} }})
+}
+
+// Output is one payment of a batch transfer.
+type Output struct {
+	Destination string `json:"destination"`
+	Symbol      string `json:"symbol"`
+	Amount      uint64 `json:"amount"`
+}
+
+// Batch is the payload of a TypeBatch transaction.
+type Batch struct {
+	Outputs []Output
+}
+
+// NewBatchTransaction returns a transaction paying every output from source.
+func NewBatchTransaction(source string, outputs []Output, memo string, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeBatch, source, &Batch{Outputs: outputs}, memo, tm)
+}
+
+// Totals returns the amount paid by the batch in each symbol. It returns
+// false if a total overflows.
+func (p *Batch) Totals() (map[string]uint64, bool) {
+	totals := make(map[string]uint64)
+	for _, o := range p.Outputs {
+		total := totals[o.Symbol] + o.Amount
+		if total < o.Amount {
+			return nil, false
+		}
+		totals[o.Symbol] = total
+	}
+	return totals, true
+}
+
+// Encode implements Payload.
+func (p *Batch) Encode(e *codec.Encoder) {
+	e.Uint64(uint64(len(p.Outputs)))
+	for _, o := range p.Outputs {
+		e.String(o.Destination)
+		e.String(o.Symbol)
+		e.Uint64(o.Amount)
+	}
+}
+
+// Decode implements Payload.
+func (p *Batch) Decode(d *codec.Decoder) {
+	n := d.Uint64()
+	if n > MaxBatchOutputs {
+		// leave the excess unread so decoding fails with trailing data
+		// rather than allocating for a hostile count.
+		return
+	}
+	p.Outputs = make([]Output, 0, n)
+	for i := uint64(0); i < n && d.Err() == nil; i++ {
+		p.Outputs = append(p.Outputs, Output{
+			Destination: d.String(),
+			Symbol:      d.String(),
+			Amount:      d.Uint64(),
+		})
+	}
+}
+
+// Validate implements Payload.
+func (p *Batch) Validate() error {
+	if len(p.Outputs) == 0 {
+		return ErrEmptyBatch
+	}
+	if len(p.Outputs) > MaxBatchOutputs {
+		return ErrBatchTooLarge
+	}
+	for _, o := range p.Outputs {
+		if o.Amount == 0 {
+			return ErrInvalidAmount
+		}
+		if !token.ValidSymbol(o.Symbol) {
+			return ErrInvalidSymbol
+		}
+		if err := keys.ValidateAddress(o.Destination); err != nil {
+			return ErrInvalidDestination
+		}
+	}
+	if _, ok := p.Totals(); !ok {
+		return ErrInvalidAmount
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package tran
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBatchValidate verifies the batch payload rules.
+func TestBatchValidate(t *testing.T) {
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	dest, err := keys.GetAddress(k)
+	assert.Nil(t, err)
+
+	valid := Batch{Outputs: []Output{{dest, "RKY", 5}, {dest, "LOLA", 7}}}
+	assert.Nil(t, valid.Validate())
+
+	cases := []struct {
+		outputs []Output
+		err     error
+	}{
+		{nil, ErrEmptyBatch},
+		{make([]Output, MaxBatchOutputs+1), ErrBatchTooLarge},
+		{[]Output{{dest, "RKY", 0}}, ErrInvalidAmount},
+		{[]Output{{dest, "rky", 5}}, ErrInvalidSymbol},
+		{[]Output{{"not_an_address", "RKY", 5}}, ErrInvalidDestination},
+		{[]Output{{dest, "RKY", math.MaxUint64}, {dest, "RKY", 1}}, ErrInvalidAmount},
+	}
+	for _, c := range cases {
+		p := Batch{Outputs: c.outputs}
+		assert.Equal(t, c.err, p.Validate())
+	}
+}
+
+// TestBatchPayload verifies the outputs survive the payload encoding.
+func TestBatchPayload(t *testing.T) {
+	outputs := []Output{{"bob", "RKY", 5}, {"carol", "LOLA", 7}}
+	tr, err := NewBatchTransaction("alice", outputs, "payroll", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+
+	p, err := tr.DecodePayload()
+	assert.Nil(t, err)
+	assert.Equal(t, outputs, p.(*Batch).Outputs)
+}
@@ -0,0 +1,19 @@
+package tran
+
+import "time"
+
+// TypeBurn destroys Amount of Symbol from the sender's balance, reducing the
+// token's supply.
+const TypeBurn = "burn"
+
+func init() {
+	RegisterType(TypeBurn, TypeRules{Amount: true})
+}
+
+// NewBurnTransaction returns a transaction burning amount of symbol.
+func NewBurnTransaction(symbol, source string, amount uint64, tm time.Time) (Transaction, error) {
+	t := Transaction{Type: TypeBurn, Symbol: symbol, Source: source, Amount: amount, Time: tm}
+
+	err := t.CalculateID()
+	return t, err
+}
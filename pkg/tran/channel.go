@@ -0,0 +1,204 @@
+package tran
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+)
+
+// Payment channel transaction types.
+const (
+	// TypeOpenChannel moves Amount of Symbol from the sender into a channel
+	// paying Destination. The channel is identified by the transaction's ID.
+	TypeOpenChannel = "channel-open"
+	// TypeCloseChannel submits the latest balance update of a channel. A
+	// close by the recipient settles the channel at once; a close by the
+	// sender starts the dispute window.
+	TypeCloseChannel = "channel-close"
+	// TypeSettleChannel pays out a channel once its dispute window has
+	// passed.
+	TypeSettleChannel = "channel-settle"
+)
+
+// MaxChannelWindow is the longest dispute window, in blocks, a channel can
+// have.
+const MaxChannelWindow = 525600
+
+// Errors returned when validating channel payloads.
+var (
+	ErrNoWindow       = errors.New("channel needs a dispute window")
+	ErrWindowTooLong  = errors.New("channel dispute window too long")
+	ErrMissingChannel = errors.New("missing channel id")
+	ErrUnsignedUpdate = errors.New("channel update paying the recipient must be signed")
+)
+
+func init() {
+	RegisterType(TypeOpenChannel, TypeRules{
+		NewPayload:  func() Payload { return &OpenChannel{} },
+		Amount:      true,
+		Destination: true,
+	})
+	RegisterType(TypeCloseChannel, TypeRules{NewPayload: func() Payload { return &CloseChannel{} }})
+	RegisterType(TypeSettleChannel, TypeRules{NewPayload: func() Payload { return &SettleChannel{} }})
+}
+
+// OpenChannel is the payload of a TypeOpenChannel transaction.
+type OpenChannel struct {
+	// Window is how many blocks the recipient has to answer a close by the
+	// sender with a later update.
+	Window uint64
+}
+
+// CloseChannel is the payload of a TypeCloseChannel transaction.
+type CloseChannel struct {
+	Update ChannelUpdate
+}
+
+// SettleChannel is the payload of a TypeSettleChannel transaction.
+type SettleChannel struct {
+	Channel string
+}
+
+// ChannelUpdate is an off-chain promise by a channel's sender that the
+// recipient is owed Paid in total. Each payment replaces the previous update
+// with one paying more, so only the latest needs to reach the chain.
+type ChannelUpdate struct {
+	Channel string   `json:"channel"`
+	Paid    uint64   `json:"paid"`
+	R       *big.Int `json:"r,omitempty"`
+	S       *big.Int `json:"s,omitempty"`
+}
+
+// NewOpenChannelTransaction returns a transaction opening a channel from
+// source to dest funded with amount of symbol.
+func NewOpenChannelTransaction(symbol, source, dest string, amount, window uint64, tm time.Time) (Transaction, error) {
+	t := Transaction{
+		Type:        TypeOpenChannel,
+		Symbol:      symbol,
+		Source:      source,
+		Destination: dest,
+		Amount:      amount,
+		Time:        tm,
+	}
+	t.SetPayload(&OpenChannel{Window: window})
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// NewCloseChannelTransaction returns a transaction closing a channel with
+// update.
+func NewCloseChannelTransaction(source string, update ChannelUpdate, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeCloseChannel, source, &CloseChannel{Update: update}, "", tm)
+}
+
+// NewSettleChannelTransaction returns a transaction paying out a channel
+// whose dispute window has passed.
+func NewSettleChannelTransaction(source, channel string, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeSettleChannel, source, &SettleChannel{Channel: channel}, "", tm)
+}
+
+// Digest returns the hash the channel's sender signs.
+func (u *ChannelUpdate) Digest() []byte {
+	e := codec.NewEncoder(codec.Version, codec.KindChannel)
+	e.String(u.Channel)
+	e.Uint64(u.Paid)
+	sum := sha256.Sum256(e.Bytes())
+	return sum[:]
+}
+
+// Sign signs the update with the channel sender's key.
+func (u *ChannelUpdate) Sign(key *ecdsa.PrivateKey) error {
+	r, s, err := keys.Sign(key, u.Digest())
+	if err != nil {
+		return err
+	}
+	u.R = r
+	u.S = s
+	return nil
+}
+
+// Verify reports whether the update was signed by sender. An update paying
+// nothing needs no signature, since it can only favour the sender.
+func (u *ChannelUpdate) Verify(sender string) bool {
+	if u.Paid == 0 {
+		return true
+	}
+	pub, err := keys.DecodeAddress(sender)
+	if err != nil {
+		return false
+	}
+	return keys.Verify(pub, u.Digest(), u.R, u.S)
+}
+
+// Encode implements Payload.
+func (p *OpenChannel) Encode(e *codec.Encoder) {
+	e.Uint64(p.Window)
+}
+
+// Decode implements Payload.
+func (p *OpenChannel) Decode(d *codec.Decoder) {
+	p.Window = d.Uint64()
+}
+
+// Validate implements Payload.
+func (p *OpenChannel) Validate() error {
+	if p.Window == 0 {
+		return ErrNoWindow
+	}
+	if p.Window > MaxChannelWindow {
+		return ErrWindowTooLong
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *CloseChannel) Encode(e *codec.Encoder) {
+	e.String(p.Update.Channel)
+	e.Uint64(p.Update.Paid)
+	e.BigInt(p.Update.R)
+	e.BigInt(p.Update.S)
+}
+
+// Decode implements Payload.
+func (p *CloseChannel) Decode(d *codec.Decoder) {
+	p.Update.Channel = d.String()
+	p.Update.Paid = d.Uint64()
+	p.Update.R = d.BigInt()
+	p.Update.S = d.BigInt()
+}
+
+// Validate implements Payload. The signature itself is checked by the chain,
+// which knows the channel's sender.
+func (p *CloseChannel) Validate() error {
+	if p.Update.Channel == "" {
+		return ErrMissingChannel
+	}
+	if p.Update.Paid > 0 && (p.Update.R == nil || p.Update.S == nil) {
+		return ErrUnsignedUpdate
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *SettleChannel) Encode(e *codec.Encoder) {
+	e.String(p.Channel)
+}
+
+// Decode implements Payload.
+func (p *SettleChannel) Decode(d *codec.Decoder) {
+	p.Channel = d.String()
+}
+
+// Validate implements Payload.
+func (p *SettleChannel) Validate() error {
+	if p.Channel == "" {
+		return ErrMissingChannel
+	}
+	return nil
+}
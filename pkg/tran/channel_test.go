@@ -0,0 +1,48 @@
+package tran
+
+import (
+	"testing"
+
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChannelValidate verifies the payment channel payload rules.
+func TestChannelValidate(t *testing.T) {
+	assert.Nil(t, (&OpenChannel{Window: 10}).Validate())
+	assert.Equal(t, ErrNoWindow, (&OpenChannel{}).Validate())
+	assert.Nil(t, (&OpenChannel{Window: MaxChannelWindow}).Validate())
+	assert.Equal(t, ErrWindowTooLong, (&OpenChannel{Window: MaxChannelWindow + 1}).Validate())
+
+	assert.Nil(t, (&CloseChannel{Update: ChannelUpdate{Channel: "id"}}).Validate())
+	assert.Equal(t, ErrMissingChannel, (&CloseChannel{}).Validate())
+	assert.Equal(t, ErrUnsignedUpdate, (&CloseChannel{Update: ChannelUpdate{Channel: "id", Paid: 5}}).Validate())
+
+	assert.Nil(t, (&SettleChannel{Channel: "id"}).Validate())
+	assert.Equal(t, ErrMissingChannel, (&SettleChannel{}).Validate())
+}
+
+// TestChannelUpdateSignature verifies only the sender can sign an update and
+// that the signature covers the amount paid.
+func TestChannelUpdateSignature(t *testing.T) {
+	sender, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	senderAddress, err := keys.GetAddress(sender)
+	assert.Nil(t, err)
+	other, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+
+	update := ChannelUpdate{Channel: "id", Paid: 5}
+	assert.False(t, update.Verify(senderAddress))
+	assert.Nil(t, update.Sign(sender))
+	assert.True(t, update.Verify(senderAddress))
+
+	update.Paid = 6
+	assert.False(t, update.Verify(senderAddress))
+
+	assert.Nil(t, update.Sign(other))
+	assert.False(t, update.Verify(senderAddress))
+
+	assert.True(t, (&ChannelUpdate{Channel: "id"}).Verify(senderAddress))
+}
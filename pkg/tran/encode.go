@@ -0,0 +1,50 @@
+package tran
+
+import (
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// SigningBytes returns the canonical encoding of the transaction without its
+// ID and signature. Its SHA-256 digest is both the ID and the signed message.
+func (t *Transaction) SigningBytes() []byte {
+	e := codec.NewEncoder(codec.Version, codec.KindTransaction)
+	t.encodeBody(e)
+	return e.Bytes()
+}
+
+// Encode returns the canonical encoding of the whole transaction, including
+// its signature or, for multisig and script sources, its cosigners'
+// signatures and its witness.
+func (t *Transaction) Encode() []byte {
+	e := codec.NewEncoder(codec.Version, codec.KindTransaction)
+	t.encodeBody(e)
+	e.BigInt(t.R)
+	e.BigInt(t.S)
+	if len(t.Signatures) > 0 {
+		e.Uint8(uint8(len(t.Signatures)))
+		for _, sig := range t.Signatures {
+			e.Uint8(sig.Key)
+			e.BigInt(sig.R)
+			e.BigInt(sig.S)
+		}
+	}
+	if len(t.Witness) > 0 {
+		e.Data(t.Witness)
+	}
+	return e.Bytes()
+}
+
+func (t *Transaction) encodeBody(e *codec.Encoder) {
+	e.String(t.Type)
+	e.String(t.Symbol)
+	e.String(t.Source)
+	e.String(t.Destination)
+	e.Uint64(t.Amount)
+	e.String(t.Memo)
+	e.Time(t.Time)
+	// the payload is only written when present, so transactions without one
+	// encode as they did before payloads existed.
+	if len(t.Payload) > 0 {
+		e.Data(t.Payload)
+	}
+}
@@ -0,0 +1,55 @@
+package tran
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSigningBytesVector checks the canonical encoding against a hand assembled test vector.
+func TestSigningBytesVector(t *testing.T) {
+	tr, err := NewTransaction("TEST", "source_address", "dest_address", 1, "memo", time.Unix(0, 0))
+	assert.Nil(t, err)
+
+	expected := "01" + "54" + // version, kind
+		"00000000" + // type
+		"00000004" + "54455354" + // symbol
+		"0000000e" + "736f757263655f61646472657373" + // source
+		"0000000c" + "646573745f61646472657373" + // destination
+		"0000000000000001" + // amount
+		"00000004" + "6d656d6f" + // memo
+		"0000000000000000" + "00000000" // time
+	assert.Equal(t, expected, hex.EncodeToString(tr.SigningBytes()))
+
+	sum := sha256.Sum256(tr.SigningBytes())
+	assert.Equal(t, "b2971bef513816fd9dec0b06312090189e0560f616367ae2f263fc08a1090f8e", hex.EncodeToString(sum[:]))
+}
+
+// TestEncodeIncludesSignature verifies the full encoding appends the signature to the signing bytes.
+func TestEncodeIncludesSignature(t *testing.T) {
+	tr, err := NewTransaction("TEST", "source_address", "dest_address", 1, "memo", time.Unix(0, 0))
+	assert.Nil(t, err)
+	tr.R = big.NewInt(1)
+	tr.S = big.NewInt(258)
+
+	expected := hex.EncodeToString(tr.SigningBytes()) + "01" + "00000001" + "01" + "01" + "00000002" + "0102"
+	assert.Equal(t, expected, hex.EncodeToString(tr.Encode()))
+}
+
+// TestEncodingIgnoresZone verifies the ID keeps nanosecond precision but ignores the time zone.
+func TestEncodingIgnoresZone(t *testing.T) {
+	tm := time.Unix(1521000000, 123456789)
+	a, err := NewTransaction("TEST", "source_address", "dest_address", 1, "memo", tm)
+	assert.Nil(t, err)
+	b, err := NewTransaction("TEST", "source_address", "dest_address", 1, "memo", tm.In(time.FixedZone("X", -7200)))
+	assert.Nil(t, err)
+	c, err := NewTransaction("TEST", "source_address", "dest_address", 1, "memo", tm.Truncate(time.Second))
+	assert.Nil(t, err)
+
+	assert.Equal(t, a.ID, b.ID)
+	assert.NotEqual(t, a.ID, c.ID)
+}
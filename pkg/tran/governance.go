@@ -0,0 +1,137 @@
+package tran
+
+import (
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// Governance transaction types.
+const (
+	// TypeProposeParam proposes changing a chain parameter. The proposal is
+	// identified by the ID of the transaction that makes it.
+	TypeProposeParam = "propose-param"
+	// TypeVote votes for or against an open proposal.
+	TypeVote = "vote"
+)
+
+// Parameters that can be changed by governance.
+const (
+	// ParamReward is the number of whole tokens of each reward symbol minted
+	// per block.
+	ParamReward = "reward"
+	// ParamDifficulty is the divisor a block's incrementor must be a
+	// multiple of.
+	ParamDifficulty = "difficulty"
+	// ParamBlockSize is the largest encoded size of a block, in bytes.
+	ParamBlockSize = "block-size"
+)
+
+// Bounds on the values a proposal may set, so a single vote can't halt the
+// chain. The chain also limits how far one proposal may move the difficulty
+// from its current value.
+const (
+	MaxReward     = 1000
+	MinBlockSize  = 64 << 10
+	MaxBlockSize  = 16 << 20
+	MinDifficulty = 1
+	MaxDifficulty = 1 << 40
+)
+
+// Vote choices.
+const (
+	VoteYes = "yes"
+	VoteNo  = "no"
+)
+
+// Errors returned when validating governance payloads.
+var (
+	ErrUnknownParam      = errors.New("parameter can't be changed by governance")
+	ErrInvalidParamValue = errors.New("parameter value out of range")
+	ErrMissingProposal   = errors.New("missing proposal id")
+	ErrInvalidVote       = errors.New("vote must be yes or no")
+)
+
+func init() {
+	RegisterType(TypeProposeParam, TypeRules{NewPayload: func() Payload { return &ProposeParam{} }})
+	RegisterType(TypeVote, TypeRules{NewPayload: func() Payload { return &Vote{} }})
+}
+
+// ProposeParam is the payload of a TypeProposeParam transaction.
+type ProposeParam struct {
+	Param string
+	Value uint64
+}
+
+// Vote is the payload of a TypeVote transaction.
+type Vote struct {
+	Proposal string
+	Choice   string
+}
+
+// NewProposeParamTransaction returns a transaction from source proposing
+// that param be set to value.
+func NewProposeParamTransaction(source, param string, value uint64, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeProposeParam, source, &ProposeParam{Param: param, Value: value}, "", tm)
+}
+
+// NewVoteTransaction returns a transaction from source voting choice on
+// proposal.
+func NewVoteTransaction(source, proposal, choice string, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeVote, source, &Vote{Proposal: proposal, Choice: choice}, "", tm)
+}
+
+// Encode implements Payload.
+func (p *ProposeParam) Encode(e *codec.Encoder) {
+	e.String(p.Param)
+	e.Uint64(p.Value)
+}
+
+// Decode implements Payload.
+func (p *ProposeParam) Decode(d *codec.Decoder) {
+	p.Param = d.String()
+	p.Value = d.Uint64()
+}
+
+// Validate implements Payload.
+func (p *ProposeParam) Validate() error {
+	var min, max uint64
+	switch p.Param {
+	case ParamReward:
+		min, max = 0, MaxReward
+	case ParamDifficulty:
+		min, max = MinDifficulty, MaxDifficulty
+	case ParamBlockSize:
+		min, max = MinBlockSize, MaxBlockSize
+	default:
+		return ErrUnknownParam
+	}
+	if p.Value < min || p.Value > max {
+		return ErrInvalidParamValue
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *Vote) Encode(e *codec.Encoder) {
+	e.String(p.Proposal)
+	e.String(p.Choice)
+}
+
+// Decode implements Payload.
+func (p *Vote) Decode(d *codec.Decoder) {
+	p.Proposal = d.String()
+	p.Choice = d.String()
+}
+
+// Validate implements Payload.
+func (p *Vote) Validate() error {
+	if p.Proposal == "" {
+		return ErrMissingProposal
+	}
+	if p.Choice != VoteYes && p.Choice != VoteNo {
+		return ErrInvalidVote
+	}
+	return nil
+}
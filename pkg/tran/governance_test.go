@@ -0,0 +1,22 @@
+package tran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGovernanceValidate verifies the proposal and vote payload rules.
+func TestGovernanceValidate(t *testing.T) {
+	assert.Nil(t, (&ProposeParam{Param: ParamReward, Value: 0}).Validate())
+	assert.Nil(t, (&ProposeParam{Param: ParamDifficulty, Value: 1000}).Validate())
+	assert.Nil(t, (&ProposeParam{Param: ParamBlockSize, Value: 2 << 20}).Validate())
+	assert.Equal(t, ErrUnknownParam, (&ProposeParam{Param: "block-version", Value: 2}).Validate())
+	assert.Equal(t, ErrInvalidParamValue, (&ProposeParam{Param: ParamReward, Value: MaxReward + 1}).Validate())
+	assert.Equal(t, ErrInvalidParamValue, (&ProposeParam{Param: ParamDifficulty}).Validate())
+	assert.Equal(t, ErrInvalidParamValue, (&ProposeParam{Param: ParamBlockSize, Value: MinBlockSize - 1}).Validate())
+
+	assert.Nil(t, (&Vote{Proposal: "id", Choice: VoteYes}).Validate())
+	assert.Equal(t, ErrMissingProposal, (&Vote{Choice: VoteNo}).Validate())
+	assert.Equal(t, ErrInvalidVote, (&Vote{Proposal: "id", Choice: "maybe"}).Validate())
+}
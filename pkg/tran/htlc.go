@@ -0,0 +1,162 @@
+package tran
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// Hash time-locked contract transaction types.
+const (
+	// TypeHTLC locks Amount of Symbol for Destination until the preimage of
+	// the payload's hash is revealed, or refunds it to the sender once the
+	// chain reaches the timeout height.
+	TypeHTLC = "htlc"
+	// TypeHTLCRedeem reveals the preimage of a contract's hash, paying its
+	// funds to the recipient.
+	TypeHTLCRedeem = "htlc-redeem"
+	// TypeHTLCRefund returns the funds of an expired contract to its sender.
+	TypeHTLCRefund = "htlc-refund"
+)
+
+// MaxPreimageLength is the longest preimage, in bytes, a redeem may reveal.
+const MaxPreimageLength = 64
+
+// Errors returned when validating HTLC payloads.
+var (
+	ErrInvalidHashLock = errors.New("hash lock must be a SHA-256 digest")
+	ErrNoTimeout       = errors.New("contract needs a timeout height")
+	ErrMissingContract = errors.New("missing contract id")
+	ErrInvalidPreimage = errors.New("preimage must be 1 to 64 bytes")
+)
+
+func init() {
+	RegisterType(TypeHTLC, TypeRules{
+		NewPayload:  func() Payload { return &HTLC{} },
+		Amount:      true,
+		Destination: true,
+	})
+	RegisterType(TypeHTLCRedeem, TypeRules{NewPayload: func() Payload { return &HTLCRedeem{} }})
+	RegisterType(TypeHTLCRefund, TypeRules{NewPayload: func() Payload { return &HTLCRefund{} }})
+}
+
+// HTLC is the payload of a TypeHTLC transaction. The contract is identified
+// by the ID of the transaction that creates it.
+type HTLC struct {
+	// Hash is the SHA-256 digest of the secret that redeems the contract.
+	Hash []byte
+	// Timeout is the height from which the contract can only be refunded.
+	Timeout uint64
+}
+
+// HTLCRedeem is the payload of a TypeHTLCRedeem transaction.
+type HTLCRedeem struct {
+	Contract string
+	Preimage []byte
+}
+
+// HTLCRefund is the payload of a TypeHTLCRefund transaction.
+type HTLCRefund struct {
+	Contract string
+}
+
+// HashSecret returns the hash lock for secret.
+func HashSecret(secret []byte) []byte {
+	sum := sha256.Sum256(secret)
+	return sum[:]
+}
+
+// NewHTLCTransaction returns a transaction locking amount of symbol for dest
+// under p.
+func NewHTLCTransaction(symbol, source, dest string, amount uint64, p HTLC, tm time.Time) (Transaction, error) {
+	t := Transaction{
+		Type:        TypeHTLC,
+		Symbol:      symbol,
+		Source:      source,
+		Destination: dest,
+		Amount:      amount,
+		Time:        tm,
+	}
+	t.SetPayload(&p)
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// NewHTLCRedeemTransaction returns a transaction redeeming contract with
+// preimage.
+func NewHTLCRedeemTransaction(source, contract string, preimage []byte, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeHTLCRedeem, source, &HTLCRedeem{Contract: contract, Preimage: preimage}, "", tm)
+}
+
+// NewHTLCRefundTransaction returns a transaction refunding an expired
+// contract.
+func NewHTLCRefundTransaction(source, contract string, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeHTLCRefund, source, &HTLCRefund{Contract: contract}, "", tm)
+}
+
+// Encode implements Payload.
+func (p *HTLC) Encode(e *codec.Encoder) {
+	e.Data(p.Hash)
+	e.Uint64(p.Timeout)
+}
+
+// Decode implements Payload.
+func (p *HTLC) Decode(d *codec.Decoder) {
+	p.Hash = d.Data()
+	p.Timeout = d.Uint64()
+}
+
+// Validate implements Payload.
+func (p *HTLC) Validate() error {
+	if len(p.Hash) != sha256.Size {
+		return ErrInvalidHashLock
+	}
+	if p.Timeout == 0 {
+		return ErrNoTimeout
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *HTLCRedeem) Encode(e *codec.Encoder) {
+	e.String(p.Contract)
+	e.Data(p.Preimage)
+}
+
+// Decode implements Payload.
+func (p *HTLCRedeem) Decode(d *codec.Decoder) {
+	p.Contract = d.String()
+	p.Preimage = d.Data()
+}
+
+// Validate implements Payload.
+func (p *HTLCRedeem) Validate() error {
+	if p.Contract == "" {
+		return ErrMissingContract
+	}
+	if len(p.Preimage) == 0 || len(p.Preimage) > MaxPreimageLength {
+		return ErrInvalidPreimage
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *HTLCRefund) Encode(e *codec.Encoder) {
+	e.String(p.Contract)
+}
+
+// Decode implements Payload.
+func (p *HTLCRefund) Decode(d *codec.Decoder) {
+	p.Contract = d.String()
+}
+
+// Validate implements Payload.
+func (p *HTLCRefund) Validate() error {
+	if p.Contract == "" {
+		return ErrMissingContract
+	}
+	return nil
+}
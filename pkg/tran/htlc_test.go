@@ -0,0 +1,24 @@
+package tran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHTLCValidate verifies the hash time-locked contract payload rules.
+func TestHTLCValidate(t *testing.T) {
+	hash := HashSecret([]byte("secret"))
+
+	assert.Nil(t, (&HTLC{Hash: hash, Timeout: 10}).Validate())
+	assert.Equal(t, ErrInvalidHashLock, (&HTLC{Hash: hash[:31], Timeout: 10}).Validate())
+	assert.Equal(t, ErrNoTimeout, (&HTLC{Hash: hash}).Validate())
+
+	assert.Nil(t, (&HTLCRedeem{Contract: "id", Preimage: []byte("secret")}).Validate())
+	assert.Equal(t, ErrMissingContract, (&HTLCRedeem{Preimage: []byte("secret")}).Validate())
+	assert.Equal(t, ErrInvalidPreimage, (&HTLCRedeem{Contract: "id"}).Validate())
+	assert.Equal(t, ErrInvalidPreimage, (&HTLCRedeem{Contract: "id", Preimage: make([]byte, MaxPreimageLength+1)}).Validate())
+
+	assert.Nil(t, (&HTLCRefund{Contract: "id"}).Validate())
+	assert.Equal(t, ErrMissingContract, (&HTLCRefund{}).Validate())
+}
@@ -0,0 +1,98 @@
+package tran
+
+import (
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/token"
+)
+
+// Token issuance transaction types.
+const (
+	// TypeIssueToken creates a new token, crediting its initial supply to
+	// the issuer.
+	TypeIssueToken = "issue-token"
+	// TypeMint creates Amount of Symbol for Destination. Only the token's
+	// mint authority may send it.
+	TypeMint = "mint"
+)
+
+// Errors returned when validating an IssueToken payload.
+var (
+	ErrInvalidName          = errors.New("token name must be 1 to 64 bytes")
+	ErrInvalidDecimals      = errors.New("too many decimals")
+	ErrInvalidMintAuthority = errors.New("malformed mint authority address")
+	ErrNoSupply             = errors.New("token needs an initial supply or a mint authority")
+)
+
+func init() {
+	RegisterType(TypeIssueToken, TypeRules{NewPayload: func() Payload { return &IssueToken{} }})
+	RegisterType(TypeMint, TypeRules{Amount: true, Destination: true})
+}
+
+// IssueToken is the payload of a TypeIssueToken transaction.
+type IssueToken struct {
+	Symbol   string
+	Name     string
+	Decimals uint8
+	// Supply is the initial supply, in base units, credited to the issuer.
+	Supply uint64
+	// MintAuthority is the address allowed to mint more of the token, empty
+	// for a fixed supply.
+	MintAuthority string
+}
+
+// NewIssueTokenTransaction returns a transaction issuing a new token from
+// source.
+func NewIssueTokenTransaction(source string, p IssueToken, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeIssueToken, source, &p, "", tm)
+}
+
+// NewMintTransaction returns a transaction minting amount of symbol to dest.
+func NewMintTransaction(symbol, source, dest string, amount uint64, tm time.Time) (Transaction, error) {
+	t := Transaction{Type: TypeMint, Symbol: symbol, Source: source, Destination: dest, Amount: amount, Time: tm}
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// Encode implements Payload.
+func (p *IssueToken) Encode(e *codec.Encoder) {
+	e.String(p.Symbol)
+	e.String(p.Name)
+	e.Uint8(p.Decimals)
+	e.Uint64(p.Supply)
+	e.String(p.MintAuthority)
+}
+
+// Decode implements Payload.
+func (p *IssueToken) Decode(d *codec.Decoder) {
+	p.Symbol = d.String()
+	p.Name = d.String()
+	p.Decimals = d.Uint8()
+	p.Supply = d.Uint64()
+	p.MintAuthority = d.String()
+}
+
+// Validate implements Payload.
+func (p *IssueToken) Validate() error {
+	if !token.ValidSymbol(p.Symbol) {
+		return ErrInvalidSymbol
+	}
+	if len(p.Name) == 0 || len(p.Name) > token.MaxNameLength {
+		return ErrInvalidName
+	}
+	if p.Decimals > token.MaxDecimals {
+		return ErrInvalidDecimals
+	}
+	if p.MintAuthority != "" {
+		if err := keys.ValidateAddress(p.MintAuthority); err != nil {
+			return ErrInvalidMintAuthority
+		}
+	} else if p.Supply == 0 {
+		return ErrNoSupply
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package tran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIssueTokenValidate verifies the issue token payload rules.
+func TestIssueTokenValidate(t *testing.T) {
+	valid := IssueToken{Symbol: "DOG", Name: "DogCoin", Decimals: 2, Supply: 100}
+	assert.Nil(t, valid.Validate())
+
+	cases := []struct {
+		modify func(*IssueToken)
+		err    error
+	}{
+		{func(p *IssueToken) { p.Symbol = "dog" }, ErrInvalidSymbol},
+		{func(p *IssueToken) { p.Name = "" }, ErrInvalidName},
+		{func(p *IssueToken) { p.Decimals = 19 }, ErrInvalidDecimals},
+		{func(p *IssueToken) { p.MintAuthority = "not_an_address" }, ErrInvalidMintAuthority},
+		{func(p *IssueToken) { p.Supply = 0 }, ErrNoSupply},
+	}
+	for _, c := range cases {
+		p := valid
+		c.modify(&p)
+		assert.Equal(t, c.err, p.Validate())
+	}
+}
@@ -0,0 +1,75 @@
+package tran
+
+import (
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// TypeLockedTransfer moves Amount of Symbol to Destination, where it can't be
+// spent until the lock in its payload expires.
+const TypeLockedTransfer = "locked-transfer"
+
+// ErrNoLock is returned for a lock without an unlock height or time.
+var ErrNoLock = errors.New("lock needs an unlock height or time")
+
+func init() {
+	RegisterType(TypeLockedTransfer, TypeRules{
+		NewPayload:  func() Payload { return &Lock{} },
+		Amount:      true,
+		Destination: true,
+	})
+}
+
+// Lock is the payload of a TypeLockedTransfer transaction. The funds unlock
+// once the chain reaches both Height and Time; a zero value leaves that
+// condition out.
+type Lock struct {
+	Height uint64    `json:"height,omitempty"`
+	Time   time.Time `json:"time,omitempty"`
+}
+
+// NewLockedTransferTransaction returns a transfer of amount of symbol to dest
+// that dest can't spend until lock expires. A vesting schedule is a series of
+// locked transfers with increasing locks.
+func NewLockedTransferTransaction(symbol, source, dest string, amount uint64, lock Lock, memo string, tm time.Time) (Transaction, error) {
+	t := Transaction{
+		Type:        TypeLockedTransfer,
+		Symbol:      symbol,
+		Source:      source,
+		Destination: dest,
+		Amount:      amount,
+		Memo:        memo,
+		Time:        tm,
+	}
+	t.SetPayload(&lock)
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// Unlocked reports whether the lock has expired in a block at height and tm.
+func (p *Lock) Unlocked(height uint64, tm time.Time) bool {
+	return height >= p.Height && (p.Time.IsZero() || !tm.Before(p.Time))
+}
+
+// Encode implements Payload.
+func (p *Lock) Encode(e *codec.Encoder) {
+	e.Uint64(p.Height)
+	e.Time(p.Time)
+}
+
+// Decode implements Payload.
+func (p *Lock) Decode(d *codec.Decoder) {
+	p.Height = d.Uint64()
+	p.Time = d.Time()
+}
+
+// Validate implements Payload.
+func (p *Lock) Validate() error {
+	if p.Height == 0 && p.Time.IsZero() {
+		return ErrNoLock
+	}
+	return nil
+}
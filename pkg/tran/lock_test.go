@@ -0,0 +1,40 @@
+package tran
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLockUnlocked verifies a lock expires only once both its height and time
+// are reached.
+func TestLockUnlocked(t *testing.T) {
+	at := time.Unix(1000, 0).UTC()
+
+	byHeight := Lock{Height: 10}
+	assert.False(t, byHeight.Unlocked(9, at))
+	assert.True(t, byHeight.Unlocked(10, at))
+
+	byTime := Lock{Time: at}
+	assert.False(t, byTime.Unlocked(100, at.Add(-time.Second)))
+	assert.True(t, byTime.Unlocked(0, at))
+
+	both := Lock{Height: 10, Time: at}
+	assert.False(t, both.Unlocked(10, at.Add(-time.Second)))
+	assert.False(t, both.Unlocked(9, at))
+	assert.True(t, both.Unlocked(10, at))
+
+	assert.Equal(t, ErrNoLock, (&Lock{}).Validate())
+}
+
+// TestLockedTransferPayload verifies the lock survives the payload encoding.
+func TestLockedTransferPayload(t *testing.T) {
+	lock := Lock{Height: 42, Time: time.Unix(1000, 5).UTC()}
+	tr, err := NewLockedTransferTransaction("LOLA", "alice", "bob", 5, lock, "vesting", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+
+	p, err := tr.DecodePayload()
+	assert.Nil(t, err)
+	assert.Equal(t, &lock, p)
+}
@@ -0,0 +1,75 @@
+package tran
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/datravis/lolachain/pkg/keys"
+)
+
+// EncryptedMemoPrefix marks a memo encrypted to the transaction's
+// destination. The rest of the memo is the base64 encoded message sealed by
+// keys.Encrypt. Memos are free text to the chain, so a memo with the prefix
+// is not guaranteed to be well formed; only wallets interpret it.
+const EncryptedMemoPrefix = "ecies:"
+
+// MaxEncryptedMemoLength is the longest memo, in bytes, that still fits in
+// MaxMemoLength once encrypted.
+const MaxEncryptedMemoLength = 128
+
+// ErrEncryptedMemoTooLong is returned when a memo is too long to encrypt.
+var ErrEncryptedMemoTooLong = errors.New("memo too long to encrypt")
+
+// EncryptMemo encrypts memo so only the holder of dest's key can read it.
+// The destination must be a single key address, since the key is recovered
+// from the address itself.
+func EncryptMemo(memo, dest string) (string, error) {
+	if len(memo) > MaxEncryptedMemoLength {
+		return "", ErrEncryptedMemoTooLong
+	}
+	pub, err := keys.DecodeAddress(dest)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := keys.Encrypt(pub, []byte(memo))
+	if err != nil {
+		return "", err
+	}
+	return EncryptedMemoPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// IsEncryptedMemo reports whether memo was encrypted with EncryptMemo.
+func IsEncryptedMemo(memo string) bool {
+	return strings.HasPrefix(memo, EncryptedMemoPrefix)
+}
+
+// DecryptMemo returns the transaction's memo, decrypting it with key if it
+// is encrypted. Plaintext memos are returned as they are.
+func (t *Transaction) DecryptMemo(key *ecdsa.PrivateKey) (string, error) {
+	if !IsEncryptedMemo(t.Memo) {
+		return t.Memo, nil
+	}
+	sealed, err := decodeEncryptedMemo(t.Memo)
+	if err != nil {
+		return "", err
+	}
+	memo, err := keys.Decrypt(key, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(memo), nil
+}
+
+// decodeEncryptedMemo returns the sealed message of an encrypted memo.
+func decodeEncryptedMemo(memo string) ([]byte, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(memo, EncryptedMemoPrefix))
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < keys.EncryptionOverhead {
+		return nil, keys.ErrDecrypt
+	}
+	return sealed, nil
+}
@@ -0,0 +1,60 @@
+package tran
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEncryptedMemo verifies an encrypted memo fits in a transaction and can
+// only be read with the destination's key.
+func TestEncryptedMemo(t *testing.T) {
+	sender, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	source, err := keys.GetAddress(sender)
+	assert.Nil(t, err)
+	recipient, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	dest, err := keys.GetAddress(recipient)
+	assert.Nil(t, err)
+
+	longest := strings.Repeat("x", MaxEncryptedMemoLength)
+	memo, err := EncryptMemo(longest, dest)
+	assert.Nil(t, err)
+	assert.True(t, IsEncryptedMemo(memo))
+	assert.True(t, len(memo) <= MaxMemoLength)
+
+	_, err = EncryptMemo(longest+"x", dest)
+	assert.Equal(t, ErrEncryptedMemoTooLong, err)
+
+	tm := time.Unix(0, 0).UTC()
+	tx, err := NewTransaction("RKY", source, dest, 5, memo, tm)
+	assert.Nil(t, err)
+	_, _, err = tx.SignTransaction(sender)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Validate(tm))
+
+	read, err := tx.DecryptMemo(recipient)
+	assert.Nil(t, err)
+	assert.Equal(t, longest, read)
+	_, err = tx.DecryptMemo(sender)
+	assert.Equal(t, keys.ErrDecrypt, err)
+
+	plain, err := NewTransaction("RKY", source, dest, 5, "invoice 1042", tm)
+	assert.Nil(t, err)
+	read, err = plain.DecryptMemo(sender)
+	assert.Nil(t, err)
+	assert.Equal(t, "invoice 1042", read)
+
+	bad, err := NewTransaction("RKY", source, dest, 5, EncryptedMemoPrefix+"!!", tm)
+	assert.Nil(t, err)
+	_, _, err = bad.SignTransaction(sender)
+	assert.Nil(t, err)
+	assert.Nil(t, bad.Validate(tm))
+	_, err = bad.DecryptMemo(recipient)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,85 @@
+package tran
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/datravis/lolachain/pkg/keys"
+)
+
+// ErrNotCosigner is returned when a key that is not part of a multisig
+// source tries to sign for it.
+var ErrNotCosigner = errors.New("key is not a cosigner of the source address")
+
+// Signature is one cosigner's signature on a transaction from a multisig
+// address. Key is the position of the signing key in the address.
+type Signature struct {
+	Key uint8    `json:"key"`
+	R   *big.Int `json:"r"`
+	S   *big.Int `json:"s"`
+}
+
+// CosignTransaction adds key's signature to a transaction from a multisig
+// address, replacing any earlier signature by the same key. Signatures are
+// kept ordered by key so every set of cosigners encodes the same way.
+func (t *Transaction) CosignTransaction(key *ecdsa.PrivateKey) error {
+	m, err := keys.DecodeMultisigAddress(t.Source)
+	if err != nil {
+		return err
+	}
+	idx := m.IndexOf(&key.PublicKey)
+	if idx < 0 {
+		return ErrNotCosigner
+	}
+
+	sum := sha256.Sum256(t.SigningBytes())
+	r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+	if err != nil {
+		return err
+	}
+	sig := Signature{Key: uint8(idx), R: r, S: keys.NormalizeS(&key.PublicKey, s)}
+
+	sigs := []Signature{sig}
+	for _, existing := range t.Signatures {
+		if existing.Key != sig.Key {
+			sigs = append(sigs, existing)
+		}
+	}
+	sort.Slice(sigs, func(i, j int) bool { return sigs[i].Key < sigs[j].Key })
+	t.Signatures = sigs
+
+	return nil
+}
+
+// verifyMultisig checks that a transaction from a multisig address carries
+// valid signatures from at least the address's threshold of keys. Every
+// signature must be valid and listed once in key order, so a third party
+// can't pad or reorder the witness.
+func (t *Transaction) verifyMultisig() (bool, error) {
+	m, err := keys.DecodeMultisigAddress(t.Source)
+	if err != nil {
+		return false, err
+	}
+	if t.R != nil || t.S != nil || len(t.Signatures) < m.Threshold {
+		return false, nil
+	}
+
+	sum := sha256.Sum256(t.SigningBytes())
+	for i, sig := range t.Signatures {
+		if i > 0 && sig.Key <= t.Signatures[i-1].Key {
+			return false, nil
+		}
+		if int(sig.Key) >= len(m.Keys) || sig.R == nil || sig.S == nil {
+			return false, nil
+		}
+		pub := m.Keys[sig.Key]
+		if !keys.IsLowS(pub, sig.S) || !ecdsa.Verify(pub, sum[:], sig.R, sig.S) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
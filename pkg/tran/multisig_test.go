@@ -0,0 +1,94 @@
+package tran
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// multisigTransfer returns a transfer from a 2-of-3 multisig address along
+// with the cosigners' keys, ordered as in the address.
+func multisigTransfer(t *testing.T) (Transaction, []*ecdsa.PrivateKey) {
+	ks := make([]*ecdsa.PrivateKey, 3)
+	pubs := make([]*ecdsa.PublicKey, 3)
+	for i := range ks {
+		k, err := keys.GenerateKeyPair()
+		assert.Nil(t, err)
+		ks[i] = k
+		pubs[i] = &k.PublicKey
+	}
+	m, err := keys.NewMultisig(2, pubs)
+	assert.Nil(t, err)
+	source, err := m.Address()
+	assert.Nil(t, err)
+
+	ordered := make([]*ecdsa.PrivateKey, 3)
+	for _, k := range ks {
+		ordered[m.IndexOf(&k.PublicKey)] = k
+	}
+
+	dest, err := keys.GetAddress(ks[0])
+	assert.Nil(t, err)
+	tr, err := NewTransaction("LOLA", source, dest, 10, "treasury", time.Now().UTC())
+	assert.Nil(t, err)
+	return tr, ordered
+}
+
+// TestCosign checks that a multisig transaction verifies once it has enough
+// cosigners.
+func TestCosign(t *testing.T) {
+	tr, ks := multisigTransfer(t)
+
+	assert.True(t, errors.Is(tr.Validate(time.Now().UTC()), ErrMissingSignature))
+
+	assert.Nil(t, tr.CosignTransaction(ks[2]))
+	ok, err := tr.VerifyTransaction()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, tr.CosignTransaction(ks[0]))
+	assert.Nil(t, tr.CosignTransaction(ks[0]))
+	assert.Equal(t, 2, len(tr.Signatures))
+	assert.Equal(t, uint8(0), tr.Signatures[0].Key)
+	assert.Equal(t, uint8(2), tr.Signatures[1].Key)
+
+	assert.Nil(t, tr.Validate(time.Now().UTC()))
+	ok, err = tr.VerifyTransaction()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	outsider, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	assert.Equal(t, ErrNotCosigner, tr.CosignTransaction(outsider))
+}
+
+// TestMultisigTampering checks that cosigner signatures can't be reordered,
+// duplicated or mixed with a single key signature.
+func TestMultisigTampering(t *testing.T) {
+	tr, ks := multisigTransfer(t)
+	assert.Nil(t, tr.CosignTransaction(ks[0]))
+	assert.Nil(t, tr.CosignTransaction(ks[1]))
+
+	swapped := tr
+	swapped.Signatures = []Signature{tr.Signatures[1], tr.Signatures[0]}
+	ok, _ := swapped.VerifyTransaction()
+	assert.False(t, ok)
+
+	duplicated := tr
+	duplicated.Signatures = []Signature{tr.Signatures[0], tr.Signatures[0]}
+	ok, _ = duplicated.VerifyTransaction()
+	assert.False(t, ok)
+
+	mixed := tr
+	_, _, err := mixed.SignTransaction(ks[0])
+	assert.Nil(t, err)
+	ok, _ = mixed.VerifyTransaction()
+	assert.False(t, ok)
+
+	assert.NotEqual(t, tr.CalculateWitnessHash(), swapped.CalculateWitnessHash())
+}
@@ -0,0 +1,141 @@
+package tran
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+)
+
+// Name service transaction types.
+const (
+	// TypeRegisterName registers a name pointing at an address for a number
+	// of blocks, or renews and repoints a name the sender already owns.
+	TypeRegisterName = "register-name"
+	// TypeTransferName hands a name to a new owner.
+	TypeTransferName = "transfer-name"
+)
+
+// Limits on names.
+const (
+	MaxNameLength = 64
+	// MaxNameTerm is the most blocks a name can be registered or renewed for
+	// at once.
+	MaxNameTerm = 1051200
+)
+
+// Errors returned when validating name payloads.
+var (
+	ErrInvalidNameFormat = errors.New("name must be dot separated labels of a-z, 0-9 and -")
+	ErrInvalidTarget     = errors.New("malformed target address")
+	ErrInvalidTerm       = errors.New("name term must be between 1 and 1051200 blocks")
+	ErrInvalidOwner      = errors.New("malformed owner address")
+)
+
+func init() {
+	RegisterType(TypeRegisterName, TypeRules{NewPayload: func() Payload { return &RegisterName{} }})
+	RegisterType(TypeTransferName, TypeRules{NewPayload: func() Payload { return &TransferName{} }})
+}
+
+// RegisterName is the payload of a TypeRegisterName transaction.
+type RegisterName struct {
+	Name string
+	// Target is the address the name resolves to.
+	Target string
+	// Term is the number of blocks the registration lasts, or is extended by
+	// on renewal.
+	Term uint64
+}
+
+// TransferName is the payload of a TypeTransferName transaction.
+type TransferName struct {
+	Name  string
+	Owner string
+}
+
+// ValidName reports whether name is a well formed name, such as lola.dog. A
+// name has at least two labels, so it can never be mistaken for an address.
+func ValidName(name string) bool {
+	if len(name) > MaxNameLength {
+		return false
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, l := range labels {
+		if len(l) == 0 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		for _, r := range l {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NewRegisterNameTransaction returns a transaction registering or renewing
+// name.
+func NewRegisterNameTransaction(source, name, target string, term uint64, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeRegisterName, source, &RegisterName{Name: name, Target: target, Term: term}, "", tm)
+}
+
+// NewTransferNameTransaction returns a transaction handing name to owner.
+func NewTransferNameTransaction(source, name, owner string, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeTransferName, source, &TransferName{Name: name, Owner: owner}, "", tm)
+}
+
+// Encode implements Payload.
+func (p *RegisterName) Encode(e *codec.Encoder) {
+	e.String(p.Name)
+	e.String(p.Target)
+	e.Uint64(p.Term)
+}
+
+// Decode implements Payload.
+func (p *RegisterName) Decode(d *codec.Decoder) {
+	p.Name = d.String()
+	p.Target = d.String()
+	p.Term = d.Uint64()
+}
+
+// Validate implements Payload.
+func (p *RegisterName) Validate() error {
+	if !ValidName(p.Name) {
+		return ErrInvalidNameFormat
+	}
+	if err := keys.ValidateAddress(p.Target); err != nil {
+		return ErrInvalidTarget
+	}
+	if p.Term == 0 || p.Term > MaxNameTerm {
+		return ErrInvalidTerm
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *TransferName) Encode(e *codec.Encoder) {
+	e.String(p.Name)
+	e.String(p.Owner)
+}
+
+// Decode implements Payload.
+func (p *TransferName) Decode(d *codec.Decoder) {
+	p.Name = d.String()
+	p.Owner = d.String()
+}
+
+// Validate implements Payload.
+func (p *TransferName) Validate() error {
+	if !ValidName(p.Name) {
+		return ErrInvalidNameFormat
+	}
+	if err := keys.ValidateAddress(p.Owner); err != nil {
+		return ErrInvalidOwner
+	}
+	return nil
+}
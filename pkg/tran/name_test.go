@@ -0,0 +1,24 @@
+package tran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidName checks which names can be registered.
+func TestValidName(t *testing.T) {
+	for _, name := range []string{"lola.dog", "rocky-1.dog", "a.b.c"} {
+		assert.True(t, ValidName(name), name)
+	}
+	for _, name := range []string{"lola", "Lola.dog", "lola..dog", ".dog", "lola.", "-lola.dog", "lola-.dog", "lola_1.dog"} {
+		assert.False(t, ValidName(name), name)
+	}
+}
+
+// TestRegisterNameValidate verifies the name registration payload rules.
+func TestRegisterNameValidate(t *testing.T) {
+	assert.Equal(t, ErrInvalidNameFormat, (&RegisterName{Name: "lola"}).Validate())
+	assert.Equal(t, ErrInvalidTarget, (&RegisterName{Name: "lola.dog", Target: "not_an_address", Term: 1}).Validate())
+	assert.Equal(t, ErrInvalidOwner, (&TransferName{Name: "lola.dog", Owner: "not_an_address"}).Validate())
+}
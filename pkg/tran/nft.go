@@ -0,0 +1,114 @@
+package tran
+
+import (
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// Non-fungible token transaction types.
+const (
+	// TypeMintNFT creates a unique token owned by the sender.
+	TypeMintNFT = "mint-nft"
+	// TypeTransferNFT gives a unique token to Destination.
+	TypeTransferNFT = "transfer-nft"
+)
+
+// MaxNFTIDLength is the longest ID, in bytes, of a unique token.
+const MaxNFTIDLength = 64
+
+// Errors returned when validating unique token payloads.
+var (
+	ErrInvalidNFTID    = errors.New("token id must be 1 to 64 of A-Z, a-z, 0-9, '.', '-' and '_'")
+	ErrInvalidMetadata = errors.New("metadata hash must be a SHA-256 digest")
+)
+
+func init() {
+	RegisterType(TypeMintNFT, TypeRules{NewPayload: func() Payload { return &MintNFT{} }})
+	RegisterType(TypeTransferNFT, TypeRules{NewPayload: func() Payload { return &TransferNFT{} }, Destination: true})
+}
+
+// MintNFT is the payload of a TypeMintNFT transaction.
+type MintNFT struct {
+	ID string
+	// Metadata is the SHA-256 digest of the token's metadata, such as a
+	// certified photo, which is kept off chain.
+	Metadata []byte
+}
+
+// TransferNFT is the payload of a TypeTransferNFT transaction.
+type TransferNFT struct {
+	ID string
+}
+
+// ValidNFTID reports whether id is a well formed unique token ID.
+func ValidNFTID(id string) bool {
+	if len(id) == 0 || len(id) > MaxNFTIDLength {
+		return false
+	}
+	for _, r := range id {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '.' && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewMintNFTTransaction returns a transaction minting a unique token with id
+// to source.
+func NewMintNFTTransaction(source, id string, metadata []byte, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeMintNFT, source, &MintNFT{ID: id, Metadata: metadata}, "", tm)
+}
+
+// NewTransferNFTTransaction returns a transaction giving the unique token id
+// to dest.
+func NewTransferNFTTransaction(source, dest, id string, memo string, tm time.Time) (Transaction, error) {
+	t := Transaction{Type: TypeTransferNFT, Source: source, Destination: dest, Memo: memo, Time: tm}
+	t.SetPayload(&TransferNFT{ID: id})
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// Encode implements Payload.
+func (p *MintNFT) Encode(e *codec.Encoder) {
+	e.String(p.ID)
+	e.Data(p.Metadata)
+}
+
+// Decode implements Payload.
+func (p *MintNFT) Decode(d *codec.Decoder) {
+	p.ID = d.String()
+	p.Metadata = d.Data()
+}
+
+// Validate implements Payload.
+func (p *MintNFT) Validate() error {
+	if !ValidNFTID(p.ID) {
+		return ErrInvalidNFTID
+	}
+	if len(p.Metadata) != sha256.Size {
+		return ErrInvalidMetadata
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *TransferNFT) Encode(e *codec.Encoder) {
+	e.String(p.ID)
+}
+
+// Decode implements Payload.
+func (p *TransferNFT) Decode(d *codec.Decoder) {
+	p.ID = d.String()
+}
+
+// Validate implements Payload.
+func (p *TransferNFT) Validate() error {
+	if !ValidNFTID(p.ID) {
+		return ErrInvalidNFTID
+	}
+	return nil
+}
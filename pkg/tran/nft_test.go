@@ -0,0 +1,21 @@
+package tran
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNFTValidate verifies the unique token payload rules.
+func TestNFTValidate(t *testing.T) {
+	metadata := sha256.Sum256([]byte("lola.jpg"))
+
+	assert.Nil(t, (&MintNFT{ID: "lola-photo_1.jpg", Metadata: metadata[:]}).Validate())
+	assert.Equal(t, ErrInvalidNFTID, (&MintNFT{ID: "", Metadata: metadata[:]}).Validate())
+	assert.Equal(t, ErrInvalidNFTID, (&MintNFT{ID: "lola photo", Metadata: metadata[:]}).Validate())
+	assert.Equal(t, ErrInvalidNFTID, (&MintNFT{ID: strings.Repeat("a", MaxNFTIDLength+1), Metadata: metadata[:]}).Validate())
+	assert.Equal(t, ErrInvalidMetadata, (&MintNFT{ID: "lola", Metadata: metadata[:8]}).Validate())
+	assert.Equal(t, ErrInvalidNFTID, (&TransferNFT{ID: "lola/1"}).Validate())
+}
@@ -0,0 +1,118 @@
+package tran
+
+import (
+	"errors"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/token"
+)
+
+// Exchange transaction types.
+const (
+	// TypePlaceOrder places a limit order on the Base/Quote market. Its funds
+	// are held by the order until it fills or is cancelled.
+	TypePlaceOrder = "place-order"
+	// TypeCancelOrder cancels the rest of an open order, returning its funds.
+	TypeCancelOrder = "cancel-order"
+)
+
+// Order sides.
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// Errors returned when validating exchange payloads.
+var (
+	ErrInvalidMarket   = errors.New("market needs two different valid symbols")
+	ErrInvalidSide     = errors.New("side must be buy or sell")
+	ErrInvalidPrice    = errors.New("price must be greater than zero")
+	ErrInvalidQuantity = errors.New("quantity must be greater than zero")
+	ErrMissingOrder    = errors.New("missing order id")
+)
+
+func init() {
+	RegisterType(TypePlaceOrder, TypeRules{NewPayload: func() Payload { return &PlaceOrder{} }})
+	RegisterType(TypeCancelOrder, TypeRules{NewPayload: func() Payload { return &CancelOrder{} }})
+}
+
+// PlaceOrder is the payload of a TypePlaceOrder transaction. The order is
+// identified by the ID of the transaction that places it.
+type PlaceOrder struct {
+	Base  string
+	Quote string
+	Side  string
+	// Price is the limit price in base units of Quote per whole Base token.
+	Price uint64
+	// Quantity is the amount of Base to buy or sell, in base units.
+	Quantity uint64
+}
+
+// CancelOrder is the payload of a TypeCancelOrder transaction.
+type CancelOrder struct {
+	Order string
+}
+
+// NewPlaceOrderTransaction returns a transaction placing p from source.
+func NewPlaceOrderTransaction(source string, p PlaceOrder, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypePlaceOrder, source, &p, "", tm)
+}
+
+// NewCancelOrderTransaction returns a transaction cancelling order.
+func NewCancelOrderTransaction(source, order string, tm time.Time) (Transaction, error) {
+	return NewPayloadTransaction(TypeCancelOrder, source, &CancelOrder{Order: order}, "", tm)
+}
+
+// Encode implements Payload.
+func (p *PlaceOrder) Encode(e *codec.Encoder) {
+	e.String(p.Base)
+	e.String(p.Quote)
+	e.String(p.Side)
+	e.Uint64(p.Price)
+	e.Uint64(p.Quantity)
+}
+
+// Decode implements Payload.
+func (p *PlaceOrder) Decode(d *codec.Decoder) {
+	p.Base = d.String()
+	p.Quote = d.String()
+	p.Side = d.String()
+	p.Price = d.Uint64()
+	p.Quantity = d.Uint64()
+}
+
+// Validate implements Payload.
+func (p *PlaceOrder) Validate() error {
+	if !token.ValidSymbol(p.Base) || !token.ValidSymbol(p.Quote) || p.Base == p.Quote {
+		return ErrInvalidMarket
+	}
+	if p.Side != SideBuy && p.Side != SideSell {
+		return ErrInvalidSide
+	}
+	if p.Price == 0 {
+		return ErrInvalidPrice
+	}
+	if p.Quantity == 0 {
+		return ErrInvalidQuantity
+	}
+	return nil
+}
+
+// Encode implements Payload.
+func (p *CancelOrder) Encode(e *codec.Encoder) {
+	e.String(p.Order)
+}
+
+// Decode implements Payload.
+func (p *CancelOrder) Decode(d *codec.Decoder) {
+	p.Order = d.String()
+}
+
+// Validate implements Payload.
+func (p *CancelOrder) Validate() error {
+	if p.Order == "" {
+		return ErrMissingOrder
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package tran
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlaceOrderValidate verifies the order payload rules.
+func TestPlaceOrderValidate(t *testing.T) {
+	valid := PlaceOrder{Base: "RKY", Quote: "LOLA", Side: SideBuy, Price: 2, Quantity: 5}
+	assert.Nil(t, valid.Validate())
+
+	cases := []struct {
+		modify func(*PlaceOrder)
+		err    error
+	}{
+		{func(p *PlaceOrder) { p.Quote = "RKY" }, ErrInvalidMarket},
+		{func(p *PlaceOrder) { p.Base = "rky" }, ErrInvalidMarket},
+		{func(p *PlaceOrder) { p.Side = "hold" }, ErrInvalidSide},
+		{func(p *PlaceOrder) { p.Price = 0 }, ErrInvalidPrice},
+		{func(p *PlaceOrder) { p.Quantity = 0 }, ErrInvalidQuantity},
+	}
+	for _, c := range cases {
+		p := valid
+		c.modify(&p)
+		assert.Equal(t, c.err, p.Validate())
+	}
+
+	assert.Equal(t, ErrMissingOrder, (&CancelOrder{}).Validate())
+}
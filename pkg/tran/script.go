@@ -0,0 +1,29 @@
+package tran
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/script"
+)
+
+// VerifyScript runs the transaction's witness against the locking script of
+// its script source address, in a block at height and tm. It returns nil if
+// the spend is authorized.
+func (t *Transaction) VerifyScript(height uint64, tm time.Time) error {
+	lock, err := keys.DecodeScriptAddress(t.Source)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(t.SigningBytes())
+	return script.Run(t.Witness, lock, script.Env{Digest: sum[:], Height: height, Time: tm})
+}
+
+// SignatureDigest returns the message signed by keys authorizing the
+// transaction, for building the witness of a script source.
+func (t *Transaction) SignatureDigest() []byte {
+	sum := sha256.Sum256(t.SigningBytes())
+	return sum[:]
+}
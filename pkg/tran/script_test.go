@@ -0,0 +1,43 @@
+package tran
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/script"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScriptSource verifies a transfer from a script address is authorized
+// by its witness in the context of a block.
+func TestScriptSource(t *testing.T) {
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	dest, err := keys.GetAddress(k)
+	assert.Nil(t, err)
+	inner, err := script.PayToKey(&k.PublicKey)
+	assert.Nil(t, err)
+	source := keys.ScriptAddress(script.AfterHeight(5, inner))
+
+	now := time.Now().UTC()
+	tr, err := NewTransaction("LOLA", source, dest, 10, "vested", now)
+	assert.Nil(t, err)
+	assert.True(t, errors.Is(tr.Validate(now), ErrMissingWitness))
+
+	sig, err := script.Sign(k, tr.SignatureDigest())
+	assert.Nil(t, err)
+	tr.Witness = new(script.Builder).Push(sig).Script()
+	assert.Nil(t, tr.Validate(now))
+	ok, err := tr.VerifyTransaction()
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, script.ErrLocked, tr.VerifyScript(4, now))
+	assert.Nil(t, tr.VerifyScript(5, now))
+
+	tr.Witness = []byte{script.OpTrue, script.OpVerify}
+	assert.True(t, errors.Is(tr.Validate(now), ErrInvalidWitness))
+}
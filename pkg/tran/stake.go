@@ -0,0 +1,34 @@
+package tran
+
+import "time"
+
+// Staking transaction types.
+const (
+	// TypeStake moves Amount of Symbol from the sender's balance into its
+	// stake.
+	TypeStake = "stake"
+	// TypeUnstake returns Amount of Symbol from the sender's stake to its
+	// balance.
+	TypeUnstake = "unstake"
+)
+
+func init() {
+	RegisterType(TypeStake, TypeRules{Amount: true})
+	RegisterType(TypeUnstake, TypeRules{Amount: true})
+}
+
+// NewStakeTransaction returns a transaction staking amount of symbol.
+func NewStakeTransaction(symbol, source string, amount uint64, tm time.Time) (Transaction, error) {
+	t := Transaction{Type: TypeStake, Symbol: symbol, Source: source, Amount: amount, Time: tm}
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// NewUnstakeTransaction returns a transaction unstaking amount of symbol.
+func NewUnstakeTransaction(symbol, source string, amount uint64, tm time.Time) (Transaction, error) {
+	t := Transaction{Type: TypeUnstake, Symbol: symbol, Source: source, Amount: amount, Time: tm}
+
+	err := t.CalculateID()
+	return t, err
+}
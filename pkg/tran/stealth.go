@@ -0,0 +1,65 @@
+package tran
+
+import (
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+)
+
+// TypeStealthTransfer moves funds from Source to a one-time Destination
+// derived from a stealth address. The payload publishes the ephemeral key
+// the recipient needs to find the payment.
+const TypeStealthTransfer = "stealth-transfer"
+
+func init() {
+	RegisterType(TypeStealthTransfer, TypeRules{
+		NewPayload:  func() Payload { return &StealthTransfer{} },
+		Amount:      true,
+		Destination: true,
+	})
+}
+
+// StealthTransfer is the payload of a TypeStealthTransfer transaction.
+type StealthTransfer struct {
+	Ephemeral []byte
+}
+
+// NewStealthTransaction returns a transaction paying amount of symbol to a
+// fresh one-time destination for the stealth address.
+func NewStealthTransaction(symbol, source, stealthAddress string, amount uint64, memo string, tm time.Time) (Transaction, error) {
+	dest, ephemeral, err := keys.NewStealthDestination(stealthAddress)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	t := Transaction{
+		Type:        TypeStealthTransfer,
+		Symbol:      symbol,
+		Source:      source,
+		Destination: dest,
+		Amount:      amount,
+		Memo:        memo,
+		Time:        tm,
+	}
+	t.SetPayload(&StealthTransfer{Ephemeral: ephemeral})
+
+	err = t.CalculateID()
+	return t, err
+}
+
+// Encode implements Payload.
+func (p *StealthTransfer) Encode(e *codec.Encoder) {
+	e.Data(p.Ephemeral)
+}
+
+// Decode implements Payload.
+func (p *StealthTransfer) Decode(d *codec.Decoder) {
+	p.Ephemeral = d.Data()
+}
+
+// Validate implements Payload.
+func (p *StealthTransfer) Validate() error {
+	_, err := keys.DecodeEphemeralKey(p.Ephemeral)
+	return err
+}
@@ -0,0 +1,37 @@
+package tran
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStealthTransaction verifies a stealth transfer pays a one-time
+// destination the recipient can spend from.
+func TestStealthTransaction(t *testing.T) {
+	sender, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	source, err := keys.GetAddress(sender)
+	assert.Nil(t, err)
+	recipient, err := keys.GenerateStealthKeys()
+	assert.Nil(t, err)
+	address, err := recipient.Address()
+	assert.Nil(t, err)
+
+	tx, err := NewStealthTransaction("RKY", source, address, 5, "", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+	_, _, err = tx.SignTransaction(sender)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Validate(time.Unix(0, 0).UTC()))
+
+	p, err := tx.DecodePayload()
+	assert.Nil(t, err)
+	key, err := recipient.Find(tx.Destination, p.(*StealthTransfer).Ephemeral)
+	assert.Nil(t, err)
+	assert.NotNil(t, key)
+
+	assert.Equal(t, keys.ErrInvalidEphemeral, (&StealthTransfer{Ephemeral: []byte("junk")}).Validate())
+}
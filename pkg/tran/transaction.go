@@ -4,30 +4,43 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/sha256"
-	"encoding/json"
 	"math/big"
 	"time"
 
 	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/script"
 
 	"github.com/lytics/base62"
 )
 
+// Transaction types.
+const (
+	// TypeTransfer moves funds from Source to Destination. An empty Type is
+	// treated as a transfer.
+	TypeTransfer = "transfer"
+	// TypeCoinbase mints a block reward to the block's validator.
+	TypeCoinbase = "coinbase"
+)
+
 // Transaction contains information about a transaction on the blockchain.
 type Transaction struct {
-	ID          string    `json:"id,omitempty"`
-	Symbol      string    `json:"symbol"`
-	Source      string    `json:"source"`
-	Destination string    `json:"destination"`
-	Amount      float64   `json:"amount"`
-	Memo        string    `json:"memo"`
-	Time        time.Time `json:"time"`
-	R           *big.Int  `json:"r,omitempty"`
-	S           *big.Int  `json:"s,omitempty"`
+	ID          string      `json:"id,omitempty"`
+	Type        string      `json:"type,omitempty"`
+	Symbol      string      `json:"symbol"`
+	Source      string      `json:"source"`
+	Destination string      `json:"destination"`
+	Amount      uint64      `json:"amount"`
+	Memo        string      `json:"memo"`
+	Time        time.Time   `json:"time"`
+	Payload     []byte      `json:"payload,omitempty"`
+	R           *big.Int    `json:"r,omitempty"`
+	S           *big.Int    `json:"s,omitempty"`
+	Signatures  []Signature `json:"signatures,omitempty"`
+	Witness     []byte      `json:"witness,omitempty"`
 }
 
 // NewTransaction returns a new transaction.
-func NewTransaction(symbol, source, dest string, amount float64, memo string, tm time.Time) (Transaction, error) {
+func NewTransaction(symbol, source, dest string, amount uint64, memo string, tm time.Time) (Transaction, error) {
 	t := Transaction{
 		Symbol:      symbol,
 		Source:      source,
@@ -41,74 +54,82 @@ func NewTransaction(symbol, source, dest string, amount float64, memo string, tm
 	return t, err
 }
 
-// CalculateID calculates a transaction's ID.
-func (t *Transaction) CalculateID() error {
-	tmpTrans := Transaction{
-		Symbol:      t.Symbol,
-		Source:      t.Source,
-		Destination: t.Destination,
-		Amount:      t.Amount,
-		Memo:        t.Memo,
-		Time:        t.Time,
+// NewCoinbaseTransaction returns a block reward transaction paying amount of
+// symbol to the validator.
+func NewCoinbaseTransaction(symbol, validator string, amount uint64, tm time.Time) (Transaction, error) {
+	t := Transaction{
+		Type:        TypeCoinbase,
+		Symbol:      symbol,
+		Source:      validator,
+		Destination: validator,
+		Amount:      amount,
+		Memo:        "block reward",
+		Time:        tm,
 	}
-	jsonEncoded, err := json.Marshal(tmpTrans)
 
-	if err != nil {
-		return err
-	}
+	err := t.CalculateID()
+	return t, err
+}
 
-	hash := sha256.Sum256(jsonEncoded)
+// IsCoinbase reports whether the transaction is a block reward.
+func (t *Transaction) IsCoinbase() bool {
+	return t.Type == TypeCoinbase
+}
+
+// CalculateID calculates a transaction's ID.
+func (t *Transaction) CalculateID() error {
+	hash := sha256.Sum256(t.SigningBytes())
 	t.ID = base62.StdEncoding.EncodeToString(hash[:])
 
 	return nil
+}
 
+// CalculateWitnessHash returns the hash of the whole transaction, signature
+// included. Unlike the ID, it differs between two signed copies of the same
+// transaction.
+func (t *Transaction) CalculateWitnessHash() string {
+	hash := sha256.Sum256(t.Encode())
+	return base62.StdEncoding.EncodeToString(hash[:])
 }
 
-// SignTransaction sign's a transaction with a user's private key.
+// SignTransaction sign's a transaction with a user's private key. The
+// signature is normalized to low-S form.
 func (t *Transaction) SignTransaction(key *ecdsa.PrivateKey) (*big.Int, *big.Int, error) {
-	tmpTrans := Transaction{
-		ID:          t.ID,
-		Symbol:      t.Symbol,
-		Source:      t.Source,
-		Destination: t.Destination,
-		Amount:      t.Amount,
-		Memo:        t.Memo,
-		Time:        t.Time,
-	}
-	jsonEncoded, err := json.Marshal(tmpTrans)
+	sum := sha256.Sum256(t.SigningBytes())
+	r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
 	if err != nil {
 		return nil, nil, err
 	}
-
-	sum := sha256.Sum256(jsonEncoded)
-	r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+	s = keys.NormalizeS(&key.PublicKey, s)
 	t.R = r
 	t.S = s
 
-	return r, s, err
+	return r, s, nil
 }
 
-// VerifyTransaction verifies a transaction was signed by the proper private key.
+// VerifyTransaction verifies a transaction was signed by the proper private
+// key, or by enough cosigners if its source is a multisig address. High-S
+// signatures are rejected. The witness of a script source depends on the
+// block it is included in, so only its form is checked here; the chain runs
+// it with VerifyScript.
 func (t *Transaction) VerifyTransaction() (bool, error) {
-	tmpTrans := Transaction{
-		ID:          t.ID,
-		Symbol:      t.Symbol,
-		Source:      t.Source,
-		Destination: t.Destination,
-		Amount:      t.Amount,
-		Memo:        t.Memo,
-		Time:        t.Time,
+	if keys.IsMultisigAddress(t.Source) {
+		return t.verifyMultisig()
 	}
-	jsonEncoded, err := json.Marshal(tmpTrans)
-	if err != nil {
-		return false, err
+	if keys.IsScriptAddress(t.Source) {
+		return t.R == nil && t.S == nil && len(t.Signatures) == 0 && script.IsPushOnly(t.Witness), nil
 	}
-
-	sum := sha256.Sum256(jsonEncoded)
+	if t.R == nil || t.S == nil {
+		return false, nil
+	}
+	sum := sha256.Sum256(t.SigningBytes())
 
 	publicKey, err := keys.DecodeAddress(t.Source)
 	if err != nil {
 		return false, err
 	}
+	if !keys.IsLowS(publicKey, t.S) {
+		return false, nil
+	}
 	return ecdsa.Verify(publicKey, sum[:], t.R, t.S), nil
 }
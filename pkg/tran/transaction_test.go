@@ -1,6 +1,7 @@
 package tran
 
 import (
+	"math/big"
 	"testing"
 	"time"
 
@@ -14,7 +15,7 @@ func TestNewTransaction(t *testing.T) {
 	sym := "TEST"
 	source := "source_address"
 	dest := "dest_address"
-	amount := 1.0
+	amount := uint64(1)
 	memo := "memo"
 	tm := time.Unix(0, 0).UTC()
 
@@ -31,13 +32,35 @@ func TestNewTransaction(t *testing.T) {
 	}
 }
 
+// TestNewCoinbaseTransaction verifies block rewards are typed as coinbase and paid to the validator.
+func TestNewCoinbaseTransaction(t *testing.T) {
+	sym := "TEST"
+	validator := "validator_address"
+	amount := uint64(1)
+	tm := time.Unix(0, 0).UTC()
+
+	tr, err := NewCoinbaseTransaction(sym, validator, amount, tm)
+	assert.Nil(t, err)
+
+	assert.True(t, tr.IsCoinbase())
+	assert.Equal(t, validator, tr.Source)
+	assert.Equal(t, validator, tr.Destination)
+	assert.Equal(t, amount, tr.Amount)
+
+	// the type is covered by the ID, so a transfer can't pose as a coinbase.
+	transfer, err := NewTransaction(sym, validator, validator, amount, tr.Memo, tm)
+	assert.Nil(t, err)
+	assert.False(t, transfer.IsCoinbase())
+	assert.NotEqual(t, transfer.ID, tr.ID)
+}
+
 // TestCalculateId verifies we're able to calculate a transaction hash.
 func TestCalculateId(t *testing.T) {
-	id := "ajRUhpCJAkyLKcsoXw8WTDR-VtEyftekhoFi4Air1LI+"
+	id := "spcb60E3Fv1d6AsGMSCQGJ3FYPYWNnri7mP7CKEJD33+"
 	sym := "TEST"
 	source := "source_address"
 	dest := "dest_address"
-	amount := 1.0
+	amount := uint64(1)
 	memo := "memo"
 	tm := time.Unix(0, 0).UTC()
 
@@ -64,7 +87,7 @@ func TestSignAndVerify(t *testing.T) {
 
 	sym := "TEST"
 	dest := "dest_address"
-	amount := 1.0
+	amount := uint64(1)
 	memo := "memo"
 	tm := time.Unix(0, 0).UTC()
 
@@ -78,3 +101,29 @@ func TestSignAndVerify(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, true, ok)
 }
+
+// TestLowS verifies signatures are produced in low-S form and high-S copies are rejected.
+func TestLowS(t *testing.T) {
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+
+	source, err := keys.GetAddress(k)
+	assert.Nil(t, err)
+
+	tr, err := NewTransaction("TEST", source, "dest_address", 1, "memo", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+
+	_, _, err = tr.SignTransaction(k)
+	assert.Nil(t, err)
+	assert.True(t, keys.IsLowS(&k.PublicKey, tr.S))
+
+	// N-S is an equally valid ECDSA signature, it must not be accepted.
+	malleated := tr
+	malleated.S = new(big.Int).Sub(k.Curve.Params().N, tr.S)
+	assert.Equal(t, tr.ID, malleated.ID)
+	assert.NotEqual(t, tr.CalculateWitnessHash(), malleated.CalculateWitnessHash())
+
+	ok, err := malleated.VerifyTransaction()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,107 @@
+package tran
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+)
+
+// Payload is the typed data carried by transactions of a registered type,
+// stored canonically encoded in Transaction.Payload.
+type Payload interface {
+	Encode(e *codec.Encoder)
+	Decode(d *codec.Decoder)
+
+	// Validate checks the rules the payload must follow regardless of chain
+	// state.
+	Validate() error
+}
+
+// TypeRules are the stateless rules for a transaction type.
+type TypeRules struct {
+	// NewPayload returns an empty payload to decode into, or is nil if the
+	// type carries no payload.
+	NewPayload func() Payload
+
+	// Amount requires a positive Amount of a known Symbol. Types without it
+	// must leave both empty.
+	Amount bool
+
+	// Destination requires a well formed Destination address. Types without
+	// it must leave it empty.
+	Destination bool
+}
+
+var types = map[string]TypeRules{
+	TypeTransfer: {Amount: true, Destination: true},
+	TypeCoinbase: {Amount: true, Destination: true},
+}
+
+// RegisterType registers the stateless rules for a transaction type. It is
+// meant to be called from init functions, and panics if the type is already
+// registered.
+func RegisterType(txType string, rules TypeRules) {
+	if _, ok := types[txType]; ok {
+		panic(fmt.Sprintf("tran: type %s registered twice", txType))
+	}
+	types[txType] = rules
+}
+
+// LookupType returns the rules registered for a transaction type.
+func LookupType(txType string) (TypeRules, bool) {
+	rules, ok := types[txType]
+	return rules, ok
+}
+
+// NewPayloadTransaction returns a transaction of txType from source carrying
+// p. Types that move funds also need Symbol, Amount and Destination set, after
+// which the ID must be recalculated.
+func NewPayloadTransaction(txType, source string, p Payload, memo string, tm time.Time) (Transaction, error) {
+	t := Transaction{
+		Type:   txType,
+		Source: source,
+		Memo:   memo,
+		Time:   tm,
+	}
+	t.SetPayload(p)
+
+	err := t.CalculateID()
+	return t, err
+}
+
+// TransactionType returns the type of the transaction, TypeTransfer if
+// unset.
+func (t *Transaction) TransactionType() string {
+	if t.Type == "" {
+		return TypeTransfer
+	}
+	return t.Type
+}
+
+// SetPayload encodes p into the transaction's payload.
+func (t *Transaction) SetPayload(p Payload) {
+	var e codec.Encoder
+	p.Encode(&e)
+	t.Payload = e.Bytes()
+}
+
+// DecodePayload decodes the transaction's payload into the type registered
+// for it.
+func (t *Transaction) DecodePayload() (Payload, error) {
+	rules, ok := LookupType(t.TransactionType())
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	if rules.NewPayload == nil {
+		return nil, ErrUnexpectedPayload
+	}
+
+	p := rules.NewPayload()
+	d := codec.NewDecoder(t.Payload)
+	p.Decode(d)
+	if err := d.Done(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,88 @@
+package tran
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/codec"
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errEmptyNote = errors.New("empty note")
+
+type notePayload struct {
+	Note string
+}
+
+func (p *notePayload) Encode(e *codec.Encoder) { e.String(p.Note) }
+func (p *notePayload) Decode(d *codec.Decoder) { p.Note = d.String() }
+
+func (p *notePayload) Validate() error {
+	if p.Note == "" {
+		return errEmptyNote
+	}
+	return nil
+}
+
+func init() {
+	RegisterType("test-note", TypeRules{NewPayload: func() Payload { return &notePayload{} }})
+}
+
+// TestPayloadRoundTrip verifies payloads are decoded into the type registered for them.
+func TestPayloadRoundTrip(t *testing.T) {
+	tr, err := NewPayloadTransaction("test-note", "source_address", &notePayload{Note: "hello"}, "", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+
+	p, err := tr.DecodePayload()
+	assert.Nil(t, err)
+	assert.Equal(t, &notePayload{Note: "hello"}, p)
+
+	// the payload is covered by the ID.
+	other, err := NewPayloadTransaction("test-note", "source_address", &notePayload{Note: "bye"}, "", tr.Time)
+	assert.Nil(t, err)
+	assert.NotEqual(t, tr.ID, other.ID)
+}
+
+// TestValidatePayload verifies type rules and payload rules are applied by Validate.
+func TestValidatePayload(t *testing.T) {
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	source, err := keys.GetAddress(k)
+	assert.Nil(t, err)
+
+	validate := func(tr Transaction) error {
+		assert.Nil(t, tr.CalculateID())
+		_, _, err := tr.SignTransaction(k)
+		assert.Nil(t, err)
+		return tr.Validate(tr.Time)
+	}
+
+	tr, err := NewPayloadTransaction("test-note", source, &notePayload{Note: "hello"}, "", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+	assert.Nil(t, validate(tr))
+
+	empty := tr
+	empty.SetPayload(&notePayload{})
+	assert.True(t, errors.Is(validate(empty), errEmptyNote))
+
+	truncated := tr
+	truncated.Payload = tr.Payload[:2]
+	assert.True(t, errors.Is(validate(truncated), ErrInvalidPayload))
+
+	withAmount := tr
+	withAmount.Amount = 1
+	assert.True(t, errors.Is(validate(withAmount), ErrUnexpectedAmount))
+
+	withDest := tr
+	withDest.Destination = source
+	assert.True(t, errors.Is(validate(withDest), ErrUnexpectedDest))
+
+	transfer := validTransaction(t)
+	transfer.Payload = tr.Payload
+	assert.True(t, errors.Is(transfer.Validate(transfer.Time), ErrBadID))
+	assert.Nil(t, transfer.CalculateID())
+	assert.True(t, errors.Is(transfer.Validate(transfer.Time), ErrUnexpectedPayload))
+}
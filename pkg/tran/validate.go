@@ -0,0 +1,158 @@
+package tran
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+	"github.com/datravis/lolachain/pkg/script"
+	"github.com/datravis/lolachain/pkg/token"
+)
+
+const (
+	// MaxMemoLength is the longest memo, in bytes, a transaction may carry.
+	MaxMemoLength = 256
+
+	// MaxFutureDrift is how far past the reference time a transaction may be
+	// timestamped.
+	MaxFutureDrift = 2 * time.Minute
+)
+
+// Errors returned by Validate, wrapped in a *ValidationError.
+var (
+	ErrUnknownType        = errors.New("unknown transaction type")
+	ErrBadID              = errors.New("id does not match contents")
+	ErrInvalidAmount      = errors.New("amount must be greater than zero")
+	ErrUnexpectedAmount   = errors.New("type does not take an amount or symbol")
+	ErrInvalidSymbol      = errors.New("malformed symbol")
+	ErrInvalidSource      = errors.New("malformed source address")
+	ErrMissingDestination = errors.New("missing destination")
+	ErrInvalidDestination = errors.New("malformed destination address")
+	ErrUnexpectedDest     = errors.New("type does not take a destination")
+	ErrInvalidPayload     = errors.New("malformed payload")
+	ErrUnexpectedPayload  = errors.New("type does not take a payload")
+	ErrMemoTooLong        = errors.New("memo too long")
+	ErrFutureTimestamp    = errors.New("timestamp too far in the future")
+	ErrMissingSignature   = errors.New("missing signature")
+	ErrUnexpectedCosigner = errors.New("cosigner signatures are only valid for multisig sources")
+	ErrMissingWitness     = errors.New("missing witness")
+	ErrInvalidWitness     = errors.New("witness may only push data")
+	ErrUnexpectedWitness  = errors.New("witness is only valid for script sources")
+	ErrUnexpectedSig      = errors.New("script sources are only authorized by their witness")
+)
+
+// ValidationError reports which rule a transaction failed.
+type ValidationError struct {
+	ID  string
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("Transaction invalid: %s: %s", e.ID, e.Err)
+}
+
+// Unwrap returns the rule that failed, for use with errors.Is.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the rules a transaction must follow regardless of chain
+// state, such as well formed symbols and addresses, including the rules
+// registered for its type and those of its payload. Timestamps are checked
+// against now, which is the current time for the mempool and the block time
+// for transactions in a block. Signatures are checked separately by
+// VerifyTransaction.
+func (t *Transaction) Validate(now time.Time) error {
+	invalid := func(err error) error {
+		return &ValidationError{ID: t.ID, Err: err}
+	}
+
+	rules, ok := LookupType(t.TransactionType())
+	if !ok {
+		return invalid(ErrUnknownType)
+	}
+
+	id := *t
+	if err := id.CalculateID(); err != nil {
+		return err
+	}
+	if id.ID != t.ID {
+		return invalid(ErrBadID)
+	}
+
+	if rules.Amount {
+		if t.Amount == 0 {
+			return invalid(ErrInvalidAmount)
+		}
+		if !token.ValidSymbol(t.Symbol) {
+			return invalid(ErrInvalidSymbol)
+		}
+	} else if t.Amount != 0 || t.Symbol != "" {
+		return invalid(ErrUnexpectedAmount)
+	}
+	if err := keys.ValidateAddress(t.Source); err != nil {
+		return invalid(ErrInvalidSource)
+	}
+	if rules.Destination {
+		if t.Destination == "" {
+			return invalid(ErrMissingDestination)
+		}
+		if err := keys.ValidateAddress(t.Destination); err != nil {
+			return invalid(ErrInvalidDestination)
+		}
+	} else if t.Destination != "" {
+		return invalid(ErrUnexpectedDest)
+	}
+	if rules.NewPayload == nil {
+		if len(t.Payload) > 0 {
+			return invalid(ErrUnexpectedPayload)
+		}
+	} else {
+		p, err := t.DecodePayload()
+		if err != nil {
+			return invalid(ErrInvalidPayload)
+		}
+		if err := p.Validate(); err != nil {
+			return invalid(err)
+		}
+	}
+	if len(t.Memo) > MaxMemoLength {
+		return invalid(ErrMemoTooLong)
+	}
+	if t.Time.After(now.Add(MaxFutureDrift)) {
+		return invalid(ErrFutureTimestamp)
+	}
+	// the height script addresses activate at is checked by the chain; see
+	// chain.Params.
+	if keys.IsScriptAddress(t.Source) {
+		if len(t.Witness) == 0 {
+			return invalid(ErrMissingWitness)
+		}
+		if !script.IsPushOnly(t.Witness) {
+			return invalid(ErrInvalidWitness)
+		}
+		if t.R != nil || t.S != nil || len(t.Signatures) > 0 {
+			return invalid(ErrUnexpectedSig)
+		}
+		return nil
+	}
+	if len(t.Witness) > 0 {
+		return invalid(ErrUnexpectedWitness)
+	}
+	// as are multisig addresses.
+	if keys.IsMultisigAddress(t.Source) {
+		if len(t.Signatures) == 0 {
+			return invalid(ErrMissingSignature)
+		}
+	} else {
+		if t.R == nil || t.S == nil {
+			return invalid(ErrMissingSignature)
+		}
+		if len(t.Signatures) > 0 {
+			return invalid(ErrUnexpectedCosigner)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package tran
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/datravis/lolachain/pkg/keys"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validTransaction(t *testing.T) Transaction {
+	k, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	source, err := keys.GetAddress(k)
+	assert.Nil(t, err)
+
+	d, err := keys.GenerateKeyPair()
+	assert.Nil(t, err)
+	dest, err := keys.GetAddress(d)
+	assert.Nil(t, err)
+
+	tr, err := NewTransaction("RKY", source, dest, 1, "memo", time.Unix(0, 0).UTC())
+	assert.Nil(t, err)
+	_, _, err = tr.SignTransaction(k)
+	assert.Nil(t, err)
+
+	return tr
+}
+
+// TestValidate verifies a well formed transaction passes validation.
+func TestValidate(t *testing.T) {
+	tr := validTransaction(t)
+	assert.Nil(t, tr.Validate(tr.Time))
+}
+
+// TestValidateRules verifies each rule is reported with its typed error.
+func TestValidateRules(t *testing.T) {
+	cases := []struct {
+		modify func(*Transaction)
+		err    error
+	}{
+		{func(tr *Transaction) { tr.Type = "bogus" }, ErrUnknownType},
+		{func(tr *Transaction) { tr.ID = "forged" }, ErrBadID},
+		{func(tr *Transaction) { tr.Amount = 0 }, ErrInvalidAmount},
+		{func(tr *Transaction) { tr.Symbol = "nope!" }, ErrInvalidSymbol},
+		{func(tr *Transaction) { tr.Source = "source_address" }, ErrInvalidSource},
+		{func(tr *Transaction) { tr.Destination = "" }, ErrMissingDestination},
+		{func(tr *Transaction) { tr.Destination = "dest_address" }, ErrInvalidDestination},
+		{func(tr *Transaction) { tr.Memo = strings.Repeat("m", MaxMemoLength+1) }, ErrMemoTooLong},
+		{func(tr *Transaction) { tr.Time = tr.Time.Add(MaxFutureDrift + time.Second) }, ErrFutureTimestamp},
+		{func(tr *Transaction) { tr.S = nil }, ErrMissingSignature},
+	}
+
+	for _, c := range cases {
+		tr := validTransaction(t)
+		now := tr.Time
+		c.modify(&tr)
+		if c.err != ErrBadID {
+			assert.Nil(t, tr.CalculateID())
+		}
+
+		err := tr.Validate(now)
+		assert.True(t, errors.Is(err, c.err), "expected %s, got %v", c.err, err)
+
+		var verr *ValidationError
+		if assert.True(t, errors.As(err, &verr)) {
+			assert.Equal(t, tr.ID, verr.ID)
+		}
+	}
+}